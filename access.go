@@ -0,0 +1,171 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import "errors"
+
+// A Capability is a Linux capability number, as in <linux/capability.h>.
+type Capability uint8
+
+// The Linux capabilities.  CapDacOverride, CapDacReadSearch, and CapFowner
+// are consulted by the permission checks in this package.
+const (
+	CapChown         Capability = iota /* change file ownership */
+	CapDacOverride                     /* bypass read, write, and execute checks */
+	CapDacReadSearch                   /* bypass read checks, and search on directories */
+	CapFowner                          /* act as the owner of any file */
+	CapFsetid
+	CapKill
+	CapSetgid
+	CapSetuid
+	CapSetpcap
+	CapLinuxImmutable
+	CapNetBindService
+	CapNetBroadcast
+	CapNetAdmin
+	CapNetRaw
+	CapIpcLock
+	CapIpcOwner
+	CapSysModule
+	CapSysRawio
+	CapSysChroot
+	CapSysPtrace
+	CapSysPacct
+	CapSysAdmin
+	CapSysBoot
+	CapSysNice
+	CapSysResource
+	CapSysTime
+	CapSysTtyConfig
+	CapMknod
+	CapLease
+	CapAuditWrite
+	CapAuditControl
+	CapSetfcap
+	CapMacOverride
+	CapMacAdmin
+	CapSyslog
+	CapWakeAlarm
+	CapBlockSuspend
+	CapAuditRead
+	CapPerfmon
+	CapBpf
+	CapCheckpointRestore
+
+	CapLast = CapCheckpointRestore
+)
+
+// A CapSet is a set of capabilities, bit n holds capability n.
+type CapSet uint64
+
+// NewCapSet returns a set holding the given capabilities.
+func NewCapSet(caps ...Capability) CapSet {
+	var s CapSet
+	for _, c := range caps {
+		s |= 1 << c
+	}
+	return s
+}
+
+// Has reports whether c is in the set.
+func (s CapSet) Has(c Capability) bool {
+	return c < 64 && s&(1<<c) != 0
+}
+
+// A Credential is the identity a process uses for file access checks: the
+// file system uid and gid, the supplementary groups, and the effective
+// capabilities.  Note that uid 0 carries no special meaning here, a root
+// process passes the checks through its capabilities.
+type Credential struct {
+	Uid    uint32
+	Gid    uint32
+	Groups []uint32
+	Caps   CapSet
+}
+
+// ErrorCredential is returned when the process credential cannot be read.
+var ErrorCredential = errors.New("Unable To Read Credential")
+
+// InGroup reports whether gid is the primary or a supplementary group.
+func (c *Credential) InGroup(gid uint32) bool {
+	if c.Gid == gid {
+		return true
+	}
+	for _, g := range c.Groups {
+		if g == gid {
+			return true
+		}
+	}
+	return false
+}
+
+// Access is a set of requested permissions, with the same values as the
+// access(2) R_OK, W_OK, and X_OK flags.
+type Access uint8
+
+const (
+	AccessExec  Access = 1 << iota /* execute, or search for a directory */
+	AccessWrite                    /* write */
+	AccessRead                     /* read */
+)
+
+// Allows reports whether a process with the credential c may access a file
+// with the Mode m and the given owner.  This reproduces generic_permission()
+// of the Linux kernel without ACLs:
+//
+// - Only one class applies: owner when the uid matches, else group when the
+// gid is one of the credential groups, else other.  An owner denied by the
+// owner bits is not rescued by the group or other bits.
+//
+// - CapDacReadSearch grants read on anything, and search on directories.
+//
+// - CapDacOverride grants everything on directories, read and write on
+// other files, and execute only when at least one execute bit is set.
+func (m Mode) Allows(c *Credential, ownerUID, ownerGID uint32, want Access) bool {
+	want &= AccessRead | AccessWrite | AccessExec
+	var perm Access
+	switch {
+	case c.Uid == ownerUID:
+		perm = Access(m>>6) & 7
+	case c.InGroup(ownerGID):
+		perm = Access(m>>3) & 7
+	default:
+		perm = Access(m) & 7
+	}
+	if want&^perm == 0 {
+		return true
+	}
+
+	if m.Type() == ModeDir {
+		if want&AccessWrite == 0 && c.Caps.Has(CapDacReadSearch) {
+			return true
+		}
+		return c.Caps.Has(CapDacOverride)
+	}
+	if want == AccessRead && c.Caps.Has(CapDacReadSearch) {
+		return true
+	}
+	if want&AccessExec == 0 || m&execAll != 0 {
+		return c.Caps.Has(CapDacOverride)
+	}
+	return false
+}
+
+// CanChmod reports whether a process with the credential c may change the
+// mode of a file owned by ownerUID, that is it owns the file or has
+// CapFowner.
+func (c *Credential) CanChmod(ownerUID uint32) bool {
+	return c.Uid == ownerUID || c.Caps.Has(CapFowner)
+}
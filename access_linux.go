@@ -0,0 +1,84 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// AccessCredential returns the Credential access(2) checks with for the
+// running process: the real uid and gid, the supplementary groups, and the
+// permitted capabilities when the real uid is 0, none otherwise.  The values
+// are read from /proc/self/status.
+func AccessCredential() (*Credential, error) {
+	return procCredential("/proc/self/status")
+}
+
+// procCredential parses the Uid, Gid, Groups, and CapPrm lines of a
+// /proc/<pid>/status file.
+func procCredential(file string) (*Credential, error) {
+	dat, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrorCredential, err)
+	}
+	var c Credential
+	var caps CapSet
+	seen := 0
+	sc := bufio.NewScanner(bytes.NewReader(dat))
+	for sc.Scan() {
+		key, val, _ := bytes.Cut(sc.Bytes(), []byte(":"))
+		f := bytes.Fields(val)
+		var v uint64
+		switch string(key) {
+		case "Uid", "Gid":
+			if len(f) == 0 {
+				return nil, fmt.Errorf("%w: empty %s field in %s", ErrorCredential, key, file)
+			}
+			v, err = strconv.ParseUint(string(f[0]), 10, 32)
+			if key[0] == 'U' {
+				c.Uid = uint32(v)
+			} else {
+				c.Gid = uint32(v)
+			}
+		case "Groups":
+			for _, g := range f {
+				if v, err = strconv.ParseUint(string(g), 10, 32); err != nil {
+					break
+				}
+				c.Groups = append(c.Groups, uint32(v))
+			}
+		case "CapPrm":
+			v, err = strconv.ParseUint(string(bytes.TrimSpace(val)), 16, 64)
+			caps = CapSet(v)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad %s field %q in %s", ErrorCredential, key, val, file)
+		}
+		seen++
+	}
+	if seen < 4 {
+		return nil, fmt.Errorf("%w: missing fields in %s", ErrorCredential, file)
+	}
+	if c.Uid == 0 {
+		c.Caps = caps
+	}
+	return &c, nil
+}
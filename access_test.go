@@ -0,0 +1,32 @@
+package unixmode_test
+
+import (
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleMode_Allows() {
+	user := &unixmode.Credential{Uid: 1000, Gid: 1000, Groups: []uint32{10}}
+	root := &unixmode.Credential{Caps: unixmode.NewCapSet(unixmode.CapDacOverride, unixmode.CapDacReadSearch)}
+
+	// Owned by uid 1000, group wheel(10)
+	m := unixmode.Mode(0077 | unixmode.ModeRegular)
+	fmt.Println("owner read:", m.Allows(user, 1000, 10, unixmode.AccessRead))
+	fmt.Println("group read:", m.Allows(user, 0, 10, unixmode.AccessRead))
+
+	m = unixmode.Mode(0644 | unixmode.ModeRegular)
+	fmt.Println("root write:", m.Allows(root, 1000, 10, unixmode.AccessWrite))
+	fmt.Println("root exec:", m.Allows(root, 1000, 10, unixmode.AccessExec))
+	m = unixmode.Mode(0645 | unixmode.ModeRegular)
+	fmt.Println("root exec, o+x:", m.Allows(root, 1000, 10, unixmode.AccessExec))
+	m = unixmode.Mode(0000 | unixmode.ModeDir)
+	fmt.Println("root search dir:", m.Allows(root, 1000, 10, unixmode.AccessExec))
+	// Output:
+	// owner read: false
+	// group read: true
+	// root write: true
+	// root exec: false
+	// root exec, o+x: true
+	// root search dir: true
+}
@@ -0,0 +1,579 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"os/user"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// An ACLTag identifies the kind of an ACL entry.  The values are those used
+// in the system.posix_acl_access extended attribute.
+type ACLTag uint16
+
+const (
+	ACLUserObj  ACLTag = 0x01 /* user::   the file owner */
+	ACLUser     ACLTag = 0x02 /* user:q:  a named user */
+	ACLGroupObj ACLTag = 0x04 /* group::  the owning group */
+	ACLGroup    ACLTag = 0x08 /* group:q: a named group */
+	ACLMask     ACLTag = 0x10 /* mask::   upper bound for the group class */
+	ACLOther    ACLTag = 0x20 /* other::  everyone else */
+)
+
+// ACLUndefinedID is the ID of entries which carry no qualifier, and of named
+// entries whose name has not been resolved.
+const ACLUndefinedID = ^uint32(0)
+
+// ErrorACL is wrapped by errors from parsing or validating an ACL.
+var ErrorACL = errors.New("Invalid ACL")
+
+// An ACLEntry is one line of an ACL.  Named entries (ACLUser and ACLGroup)
+// have a qualifier, which is the numeric ID, the Name, or both.
+type ACLEntry struct {
+	Tag  ACLTag
+	ID   uint32
+	Name string
+	Perm Access
+}
+
+// An ACL is a POSIX.1e access control list.  Access is checked on every
+// access to the file, Default is only found on directories and is the ACL
+// inherited by new entries.
+type ACL struct {
+	Access  []ACLEntry
+	Default []ACLEntry
+}
+
+// String returns the permissions as "rwx" with '-' for missing bits.
+func (a Access) String() string {
+	var buf [3]byte
+	setIf(&buf[0], a&AccessRead != 0, 'r', '-')
+	setIf(&buf[1], a&AccessWrite != 0, 'w', '-')
+	setIf(&buf[2], a&AccessExec != 0, 'x', '-')
+	return string(buf[:])
+}
+
+// NewACL returns the minimal ACL, with only the user::, group::, and other::
+// entries, equivalent to the permission bits of m.
+func NewACL(m Mode) *ACL {
+	return &ACL{Access: []ACLEntry{
+		{Tag: ACLUserObj, ID: ACLUndefinedID, Perm: Access(m>>6) & 7},
+		{Tag: ACLGroupObj, ID: ACLUndefinedID, Perm: Access(m>>3) & 7},
+		{Tag: ACLOther, ID: ACLUndefinedID, Perm: Access(m) & 7},
+	}}
+}
+
+// IsExtended reports whether the access ACL holds more than can be expressed
+// in the permission bits, that is any named entry or a mask.
+func (a *ACL) IsExtended() bool {
+	for _, e := range a.Access {
+		switch e.Tag {
+		case ACLUser, ACLGroup, ACLMask:
+			return true
+		}
+	}
+	return false
+}
+
+// Mode returns the permission bits equivalent to the access ACL.  As with
+// stat(2) the group bits show the mask entry when there is one.
+func (a *ACL) Mode() Mode {
+	var m Mode
+	var group, mask Access
+	hasMask := false
+	for _, e := range a.Access {
+		switch e.Tag {
+		case ACLUserObj:
+			m |= Mode(e.Perm&7) << 6
+		case ACLGroupObj:
+			group = e.Perm & 7
+		case ACLMask:
+			mask, hasMask = e.Perm&7, true
+		case ACLOther:
+			m |= Mode(e.Perm & 7)
+		}
+	}
+	if hasMask {
+		group = mask
+	}
+	return m | Mode(group)<<3
+}
+
+// SetMode updates the access ACL the way chmod(2) does: the owner and other
+// bits go to user:: and other::, and the group bits go to mask:: when there
+// is one or group:: otherwise.
+func (a *ACL) SetMode(m Mode) {
+	if len(a.Access) == 0 {
+		a.Access = NewACL(m).Access
+		return
+	}
+	groupTag := ACLGroupObj
+	if a.find(a.Access, ACLMask, ACLEntry{}) >= 0 {
+		groupTag = ACLMask
+	}
+	for i := range a.Access {
+		e := &a.Access[i]
+		switch e.Tag {
+		case ACLUserObj:
+			e.Perm = Access(m>>6) & 7
+		case groupTag:
+			e.Perm = Access(m>>3) & 7
+		case ACLOther:
+			e.Perm = Access(m) & 7
+		}
+	}
+}
+
+// Effective returns the permissions an entry actually grants, that is the
+// entry masked by mask:: for the named and group:: entries.
+func (a *ACL) Effective(entries []ACLEntry, e ACLEntry) Access {
+	switch e.Tag {
+	case ACLUser, ACLGroupObj, ACLGroup:
+		if i := a.find(entries, ACLMask, ACLEntry{}); i >= 0 {
+			return e.Perm & entries[i].Perm
+		}
+	}
+	return e.Perm
+}
+
+// CalcMask sets the mask:: entry of both lists to the union of the group
+// class, as setfacl does after a modification.  A mask is added when there
+// are named entries and none is present.
+func (a *ACL) CalcMask() {
+	a.Access = calcMask(a.Access)
+	a.Default = calcMask(a.Default)
+}
+
+func calcMask(entries []ACLEntry) []ACLEntry {
+	var union Access
+	named := false
+	for _, e := range entries {
+		switch e.Tag {
+		case ACLUser, ACLGroup:
+			named = true
+			union |= e.Perm
+		case ACLGroupObj:
+			union |= e.Perm
+		}
+	}
+	for i := range entries {
+		if entries[i].Tag == ACLMask {
+			entries[i].Perm = union
+			return entries
+		}
+	}
+	if named {
+		entries = append(entries, ACLEntry{Tag: ACLMask, ID: ACLUndefinedID, Perm: union})
+		sortACL(entries)
+	}
+	return entries
+}
+
+// Valid checks the rules of acl_valid(3): exactly one of each of user::,
+// group::, and other::, a mask:: when there are named entries, and no two
+// named entries with the same qualifier.  An empty Default list is valid.
+func (a *ACL) Valid() error {
+	if err := validACL(a.Access); err != nil {
+		return err
+	}
+	if len(a.Default) > 0 {
+		if err := validACL(a.Default); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	return nil
+}
+
+func validACL(entries []ACLEntry) error {
+	count := map[ACLTag]int{}
+	seen := map[string]bool{}
+	for _, e := range entries {
+		count[e.Tag]++
+		switch e.Tag {
+		case ACLUser, ACLGroup:
+			q := e.Tag.String() + ":" + e.qualifier()
+			if seen[q] {
+				return fmt.Errorf("%w: duplicate entry %q", ErrorACL, q)
+			}
+			seen[q] = true
+		case ACLUserObj, ACLGroupObj, ACLMask, ACLOther:
+		default:
+			return fmt.Errorf("%w: unknown tag %#x", ErrorACL, uint16(e.Tag))
+		}
+	}
+	for _, t := range []ACLTag{ACLUserObj, ACLGroupObj, ACLOther} {
+		if count[t] != 1 {
+			return fmt.Errorf("%w: need one %s:: entry, found %d", ErrorACL, t, count[t])
+		}
+	}
+	if count[ACLMask] > 1 || count[ACLMask] == 0 && count[ACLUser]+count[ACLGroup] > 0 {
+		return fmt.Errorf("%w: need one mask:: entry with named entries, found %d", ErrorACL, count[ACLMask])
+	}
+	return nil
+}
+
+// Resolve fills in the ID of named entries which only have a Name, using the
+// local user and group databases.
+func (a *ACL) Resolve() error {
+	for _, list := range [][]ACLEntry{a.Access, a.Default} {
+		for i := range list {
+			e := &list[i]
+			if e.ID != ACLUndefinedID || e.Name == "" {
+				continue
+			}
+			var id string
+			switch e.Tag {
+			case ACLUser:
+				u, err := user.Lookup(e.Name)
+				if err != nil {
+					return err
+				}
+				id = u.Uid
+			case ACLGroup:
+				g, err := user.LookupGroup(e.Name)
+				if err != nil {
+					return err
+				}
+				id = g.Gid
+			default:
+				continue
+			}
+			v, err := strconv.ParseUint(id, 10, 32)
+			if err != nil {
+				return fmt.Errorf("%w: non-numeric id %q for %q", ErrorACL, id, e.Name)
+			}
+			e.ID = uint32(v)
+		}
+	}
+	return nil
+}
+
+// String returns the long tag name as used by getfacl.
+func (t ACLTag) String() string {
+	switch t {
+	case ACLUserObj, ACLUser:
+		return "user"
+	case ACLGroupObj, ACLGroup:
+		return "group"
+	case ACLMask:
+		return "mask"
+	case ACLOther:
+		return "other"
+	}
+	return "?"
+}
+
+func (e ACLEntry) qualifier() string {
+	switch {
+	case e.Tag != ACLUser && e.Tag != ACLGroup:
+		return ""
+	case e.Name != "":
+		return e.Name
+	case e.ID != ACLUndefinedID:
+		return strconv.FormatUint(uint64(e.ID), 10)
+	}
+	return ""
+}
+
+// String returns the entry in the getfacl format, like "user:bob:rwx".
+func (e ACLEntry) String() string {
+	return e.Tag.String() + ":" + e.qualifier() + ":" + e.Perm.String()
+}
+
+// String returns the ACL in the format printed by getfacl, without the
+// "# file:" header.  Entries limited by the mask carry an "#effective:"
+// comment, and default entries are prefixed with "default:".
+func (a *ACL) String() string {
+	var b strings.Builder
+	writeACL(&b, a, a.Access, "")
+	writeACL(&b, a, a.Default, "default:")
+	return b.String()
+}
+
+// Format returns the ACL with the getfacl header naming the file, the owner,
+// and the group, followed by a blank line as getfacl prints it.
+func (a *ACL) Format(file, owner, group string) string {
+	return fmt.Sprintf("# file: %s\n# owner: %s\n# group: %s\n%s\n", file, owner, group, a.String())
+}
+
+func writeACL(b *strings.Builder, a *ACL, entries []ACLEntry, prefix string) {
+	for _, e := range entries {
+		line := prefix + e.String()
+		b.WriteString(line)
+		if eff := a.Effective(entries, e); eff != e.Perm {
+			// Pad with tabs out to the 32nd column, as getfacl does, and
+			// always separate a longer entry by at least one tab
+			for col := len(line); ; {
+				b.WriteByte('\t')
+				if col = col&^7 + 8; col >= 32 {
+					break
+				}
+			}
+			b.WriteString("#effective:" + eff.String())
+		}
+		b.WriteByte('\n')
+	}
+}
+
+// ParseACL reads an ACL in the getfacl text format.  Comment lines and
+// "#effective:" annotations are ignored, so the output of getfacl can be
+// read back.  Entries may use the short forms accepted by setfacl, such as
+// "u:bob:rw" or "d:g::r-x".
+func ParseACL(text string) (*ACL, error) {
+	a := &ACL{}
+	for n, line := range strings.Split(text, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		def, e, err := parseACLEntry(line, true)
+		if err == nil && e.Perm&accessCondExec != 0 {
+			err = fmt.Errorf("%w: %q: invalid permission 'X'", ErrorACL, line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		if def {
+			a.Default = append(a.Default, e)
+		} else {
+			a.Access = append(a.Access, e)
+		}
+	}
+	sortACL(a.Access)
+	sortACL(a.Default)
+	if err := a.Valid(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Modify applies a setfacl -m specification, a comma separated list of
+// entries such as "u:bob:rwx,g:staff:r-x,d:u::rw".  Entries replace those
+// with the same tag and qualifier, and the mask is recalculated unless the
+// specification sets it.  A capital 'X' grants execute only when the ACL
+// already grants execute to someone.
+func (a *ACL) Modify(spec string) error {
+	var maskSet [2]bool
+	for _, s := range splitSpec(spec) {
+		def, e, err := parseACLEntry(s, true)
+		if err != nil {
+			return err
+		}
+		list := &a.Access
+		if def {
+			list = &a.Default
+			if len(*list) == 0 {
+				// Seed the default ACL from the access ACL, as setfacl does
+				for _, ae := range a.Access {
+					if ae.Tag != ACLUser && ae.Tag != ACLGroup && ae.Tag != ACLMask {
+						*list = append(*list, ae)
+					}
+				}
+			}
+		}
+		if e.Perm&accessCondExec != 0 {
+			e.Perm &^= accessCondExec
+			for _, le := range *list {
+				if le.Tag != ACLMask && le.Perm&AccessExec != 0 {
+					e.Perm |= AccessExec
+				}
+			}
+		}
+		if e.Tag == ACLMask {
+			maskSet[btoi(def)] = true
+		}
+		if i := a.find(*list, e.Tag, e); i >= 0 {
+			(*list)[i] = e
+		} else {
+			*list = append(*list, e)
+		}
+		sortACL(*list)
+	}
+	if !maskSet[0] {
+		a.Access = calcMask(a.Access)
+	}
+	if !maskSet[1] {
+		a.Default = calcMask(a.Default)
+	}
+	return nil
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Remove applies a setfacl -x specification, a comma separated list of
+// entries without permissions such as "u:bob,g:staff,d:u:bob".  Only named
+// entries and mask:: may be removed.
+func (a *ACL) Remove(spec string) error {
+	for _, s := range splitSpec(spec) {
+		def, e, err := parseACLEntry(s, false)
+		if err != nil {
+			return err
+		}
+		if e.Tag != ACLUser && e.Tag != ACLGroup && e.Tag != ACLMask {
+			return fmt.Errorf("%w: cannot remove %s:: entry", ErrorACL, e.Tag)
+		}
+		list := &a.Access
+		if def {
+			list = &a.Default
+		}
+		if i := a.find(*list, e.Tag, e); i >= 0 {
+			*list = append((*list)[:i], (*list)[i+1:]...)
+		}
+	}
+	a.CalcMask()
+	return nil
+}
+
+func splitSpec(spec string) []string {
+	var out []string
+	for _, s := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// find returns the index of the entry with the tag and, for named entries,
+// the same qualifier as e.
+func (a *ACL) find(entries []ACLEntry, tag ACLTag, e ACLEntry) int {
+	for i, le := range entries {
+		if le.Tag != tag {
+			continue
+		}
+		if tag != ACLUser && tag != ACLGroup {
+			return i
+		}
+		if e.ID != ACLUndefinedID && le.ID == e.ID || e.Name != "" && le.Name == e.Name {
+			return i
+		}
+	}
+	return -1
+}
+
+// accessCondExec marks a parsed 'X' until it is resolved by Modify.
+const accessCondExec Access = 8
+
+func parseACLEntry(s string, withPerm bool) (def bool, e ACLEntry, err error) {
+	fields := strings.Split(s, ":")
+	if len(fields) > 0 && (fields[0] == "default" || fields[0] == "d") {
+		def, fields = true, fields[1:]
+	}
+	bad := func(format string, a ...interface{}) (bool, ACLEntry, error) {
+		return false, ACLEntry{}, fmt.Errorf("%w: %q: %s", ErrorACL, s, fmt.Sprintf(format, a...))
+	}
+	if len(fields) == 0 {
+		return bad("missing tag")
+	}
+	e.ID = ACLUndefinedID
+	tag, fields := fields[0], fields[1:]
+	qual := ""
+	switch tag {
+	case "user", "u", "group", "g":
+		if len(fields) == 0 {
+			return bad("missing qualifier")
+		}
+		qual, fields = fields[0], fields[1:]
+	case "mask", "m", "other", "o":
+		// The qualifier field is optional for these
+		if len(fields) == 2 || !withPerm && len(fields) == 1 {
+			if fields[0] != "" {
+				return bad("%s entries take no qualifier", tag)
+			}
+			fields = fields[1:]
+		}
+	default:
+		return bad("unknown tag %q", tag)
+	}
+	switch tag[0] {
+	case 'u':
+		e.Tag = ACLUserObj
+		if qual != "" {
+			e.Tag = ACLUser
+		}
+	case 'g':
+		e.Tag = ACLGroupObj
+		if qual != "" {
+			e.Tag = ACLGroup
+		}
+	case 'm':
+		e.Tag = ACLMask
+	case 'o':
+		e.Tag = ACLOther
+	}
+	if qual != "" {
+		if v, err := strconv.ParseUint(qual, 10, 32); err == nil {
+			e.ID = uint32(v)
+		} else {
+			e.Name = qual
+		}
+	}
+
+	if !withPerm {
+		// setfacl -x accepts a trailing colon, as in "m::"
+		if len(fields) == 1 && fields[0] == "" {
+			fields = nil
+		}
+		if len(fields) != 0 {
+			return bad("unexpected permissions")
+		}
+		return def, e, nil
+	}
+	if len(fields) != 1 {
+		return bad("expected tag:qualifier:perms")
+	}
+	for _, c := range fields[0] {
+		switch c {
+		case 'r':
+			e.Perm |= AccessRead
+		case 'w':
+			e.Perm |= AccessWrite
+		case 'x':
+			e.Perm |= AccessExec
+		case 'X':
+			e.Perm |= accessCondExec
+		case '-':
+		default:
+			return bad("invalid permission %q", c)
+		}
+	}
+	return def, e, nil
+}
+
+// sortACL puts entries in the order the kernel stores them: by tag, then by
+// qualifier.
+func sortACL(entries []ACLEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Tag != entries[j].Tag {
+			return entries[i].Tag < entries[j].Tag
+		}
+		if entries[i].ID != entries[j].ID {
+			return entries[i].ID < entries[j].ID
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
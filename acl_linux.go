@@ -0,0 +1,112 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// GetACL reads the access and default ACL of a file.  A file without an
+// access ACL attribute, or on a file system without ACL support, returns the
+// minimal ACL built from its permission bits.
+func GetACL(name string) (*ACL, error) {
+	b, err := getxattr(name, XattrACLAccess)
+	a := &ACL{}
+	switch err {
+	case nil:
+		if a.Access, err = UnmarshalACLXattr(b); err != nil {
+			return nil, &fs.PathError{Op: "getacl", Path: name, Err: err}
+		}
+	case syscall.ENODATA, syscall.ENOTSUP:
+		st, err := Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		a = NewACL(st.Mode)
+	default:
+		return nil, &fs.PathError{Op: "getacl", Path: name, Err: err}
+	}
+
+	b, err = getxattr(name, XattrACLDefault)
+	switch err {
+	case nil:
+		if a.Default, err = UnmarshalACLXattr(b); err != nil {
+			return nil, &fs.PathError{Op: "getacl", Path: name, Err: err}
+		}
+	case syscall.ENODATA, syscall.ENOTSUP:
+	default:
+		return nil, &fs.PathError{Op: "getacl", Path: name, Err: err}
+	}
+	return a, nil
+}
+
+// SetACL writes the access ACL of a file, and the default ACL when the file
+// is a directory.  An empty Default removes the default ACL.  Named entries
+// are resolved to IDs first.  When the file system has no ACL support a
+// minimal ACL is still applied, through chmod(2).
+func SetACL(name string, a *ACL) error {
+	if err := a.Resolve(); err != nil {
+		return &fs.PathError{Op: "setacl", Path: name, Err: err}
+	}
+	if err := a.Valid(); err != nil {
+		return &fs.PathError{Op: "setacl", Path: name, Err: err}
+	}
+	b, err := MarshalACLXattr(a.Access)
+	if err != nil {
+		return &fs.PathError{Op: "setacl", Path: name, Err: err}
+	}
+	if err = syscall.Setxattr(name, XattrACLAccess, b, 0); err != nil {
+		if err == syscall.ENOTSUP && !a.IsExtended() && len(a.Default) == 0 {
+			return chmodKeepSpecial(name, a.Mode())
+		}
+		return &fs.PathError{Op: "setacl", Path: name, Err: err}
+	}
+
+	if len(a.Default) == 0 {
+		err = syscall.Removexattr(name, XattrACLDefault)
+		if err == syscall.ENODATA || err == syscall.ENOTSUP {
+			err = nil
+		}
+	} else if b, err = MarshalACLXattr(a.Default); err == nil {
+		err = syscall.Setxattr(name, XattrACLDefault, b, 0)
+	}
+	if err != nil {
+		return &fs.PathError{Op: "setacl", Path: name, Err: err}
+	}
+	return nil
+}
+
+// ChmodACL changes the permission bits of a file and returns the ACL as it
+// stands afterwards.  With an extended ACL the group bits set the mask:: entry,
+// leaving group:: and the named entries untouched, so the effective rights of
+// every named entry are capped by the new group bits.  This is the rule
+// chmod(2) applies in the kernel, ChmodACL makes the outcome visible.
+func ChmodACL(name string, m Mode) (*ACL, error) {
+	if err := Chmod(name, m); err != nil {
+		return nil, err
+	}
+	return GetACL(name)
+}
+
+// chmodKeepSpecial sets the permission bits while keeping the setuid,
+// setgid, and sticky bits of the file.
+func chmodKeepSpecial(name string, perm Mode) error {
+	st, err := Stat(name)
+	if err != nil {
+		return err
+	}
+	return Chmod(name, st.Mode&07000|perm&0777)
+}
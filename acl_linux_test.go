@@ -0,0 +1,61 @@
+package unixmode_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func TestSetACL(t *testing.T) {
+	f, err := os.CreateTemp("", "acl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	acl := unixmode.NewACL(0640)
+	if err := acl.Modify("u:1234:rw,g:5678:r"); err != nil {
+		t.Fatal(err)
+	}
+	if err := unixmode.SetACL(f.Name(), acl); errors.Is(err, syscall.ENOTSUP) || errors.Is(err, syscall.EOPNOTSUPP) {
+		t.Skip("no ACL support in", os.TempDir())
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	acl, err = unixmode.ChmodACL(f.Name(), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "user::rw-\n" +
+		"user:1234:rw-\t\t\t#effective:---\n" +
+		"group::r--\t\t\t#effective:---\n" +
+		"group:5678:r--\t\t\t#effective:---\n" +
+		"mask::---\n" +
+		"other::---\n"
+	if got := acl.String(); got != want {
+		t.Errorf("ChmodACL returned\n%s\nwant\n%s", got, want)
+	}
+	if got, _ := unixmode.GetACL(f.Name()); got.String() != want {
+		t.Errorf("GetACL after ChmodACL returned\n%s\nwant\n%s", got, want)
+	}
+}
+
+func ExampleUnmarshalACLXattr() {
+	b := []byte{
+		2, 0, 0, 0,
+		0x01, 0, 6, 0, 0xff, 0xff, 0xff, 0xff,
+		0x02, 0, 7, 0, 0xe8, 0x03, 0, 0,
+		0x04, 0, 4, 0, 0xff, 0xff, 0xff, 0xff,
+		0x10, 0, 5, 0, 0xff, 0xff, 0xff, 0xff,
+		0x20, 0, 0, 0, 0xff, 0xff, 0xff, 0xff,
+	}
+	entries, err := unixmode.UnmarshalACLXattr(b)
+	fmt.Println(entries, err)
+	// Output:
+	// [user::rw- user:1000:rwx group::r-- mask::r-x other::---] <nil>
+}
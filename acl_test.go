@@ -0,0 +1,100 @@
+package unixmode_test
+
+import (
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleParseACL() {
+	acl, err := unixmode.ParseACL(`# file: srv
+# owner: root
+# group: root
+user::rwx
+user:bob:rwx			#effective:r-x
+group::r-x
+group:1001:rw-			#effective:r--
+mask::r-x
+other::---
+default:user::rwx
+default:group::r-x
+default:other::---
+`)
+	fmt.Println(err)
+	fmt.Printf("%s %v\n", acl.Mode().PermString(), acl.IsExtended())
+	fmt.Print(acl)
+	// Output:
+	// <nil>
+	// rwxr-x--- true
+	// user::rwx
+	// user:bob:rwx			#effective:r-x
+	// group::r-x
+	// group:1001:rw-			#effective:r--
+	// mask::r-x
+	// other::---
+	// default:user::rwx
+	// default:group::r-x
+	// default:other::---
+}
+
+func ExampleACL_Modify() {
+	acl := unixmode.NewACL(0640)
+	acl.Modify("u:bob:rw,g:wheel:rX,d:u:bob:rwx")
+	acl.Remove("g:wheel")
+	fmt.Print(acl.Format("data", "root", "staff"))
+	acl.SetMode(0600)
+	fmt.Print(acl)
+	// Output:
+	// # file: data
+	// # owner: root
+	// # group: staff
+	// user::rw-
+	// user:bob:rw-
+	// group::r--
+	// mask::rw-
+	// other::---
+	// default:user::rw-
+	// default:user:bob:rwx
+	// default:group::r--
+	// default:mask::rwx
+	// default:other::---
+	//
+	// user::rw-
+	// user:bob:rw-			#effective:---
+	// group::r--			#effective:---
+	// mask::---
+	// other::---
+	// default:user::rw-
+	// default:user:bob:rwx
+	// default:group::r--
+	// default:mask::rwx
+	// default:other::---
+}
+
+func ExampleParseACL_invalid() {
+	acl, err := unixmode.ParseACL("user::rwx\nuser:bob:rwx\ngroup::r-x\nother::---")
+	fmt.Println(acl == nil, err)
+	_, err = unixmode.ParseACL("user::rwz")
+	fmt.Println(err)
+	// Output:
+	// true Invalid ACL: need one mask:: entry with named entries, found 0
+	// line 1: Invalid ACL: "user::rwz": invalid permission 'z'
+}
+
+func ExampleACL_Remove() {
+	acl, _ := unixmode.ParseACL("user::rw-\nuser:4000000000:rwx\nuser:build-automation-account:rwx\ngroup::r--\nmask::r--\nother::---")
+	fmt.Print(acl)
+	fmt.Println(acl.Remove("u:4000000000:,u:build-automation-account,m::"))
+	fmt.Print(acl)
+	// Output:
+	// user::rw-
+	// user:4000000000:rwx		#effective:r--
+	// user:build-automation-account:rwx	#effective:r--
+	// group::r--
+	// mask::r--
+	// other::---
+	// <nil>
+	// user::rw-
+	// group::r--
+	// other::---
+}
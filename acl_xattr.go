@@ -0,0 +1,76 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// The extended attributes holding the access and default ACL on Linux.
+const (
+	XattrACLAccess  = "system.posix_acl_access"
+	XattrACLDefault = "system.posix_acl_default"
+)
+
+// aclXattrVersion is the header of the ACL extended attribute format.
+const aclXattrVersion = 2
+
+// MarshalACLXattr encodes ACL entries into the binary format used by the
+// system.posix_acl_access and system.posix_acl_default extended attributes:
+// a little endian version 2 header followed by tag, perm, and id for each
+// entry.  Named entries must have a resolved ID, see ACL.Resolve.
+func MarshalACLXattr(entries []ACLEntry) ([]byte, error) {
+	b := make([]byte, 4, 4+8*len(entries))
+	binary.LittleEndian.PutUint32(b, aclXattrVersion)
+	for _, e := range entries {
+		id := ACLUndefinedID
+		if e.Tag == ACLUser || e.Tag == ACLGroup {
+			if e.ID == ACLUndefinedID {
+				return nil, fmt.Errorf("%w: entry %q has no numeric id", ErrorACL, e)
+			}
+			id = e.ID
+		}
+		var ent [8]byte
+		binary.LittleEndian.PutUint16(ent[0:], uint16(e.Tag))
+		binary.LittleEndian.PutUint16(ent[2:], uint16(e.Perm&7))
+		binary.LittleEndian.PutUint32(ent[4:], id)
+		b = append(b, ent[:]...)
+	}
+	return b, nil
+}
+
+// UnmarshalACLXattr decodes the binary format of an ACL extended attribute.
+func UnmarshalACLXattr(b []byte) ([]ACLEntry, error) {
+	if len(b) < 4 || (len(b)-4)%8 != 0 {
+		return nil, fmt.Errorf("%w: bad xattr length %d", ErrorACL, len(b))
+	}
+	if v := binary.LittleEndian.Uint32(b); v != aclXattrVersion {
+		return nil, fmt.Errorf("%w: unsupported xattr version %d", ErrorACL, v)
+	}
+	var entries []ACLEntry
+	for b = b[4:]; len(b) > 0; b = b[8:] {
+		e := ACLEntry{
+			Tag:  ACLTag(binary.LittleEndian.Uint16(b[0:])),
+			Perm: Access(binary.LittleEndian.Uint16(b[2:]) & 7),
+			ID:   binary.LittleEndian.Uint32(b[4:]),
+		}
+		if e.Tag != ACLUser && e.Tag != ACLGroup {
+			e.ID = ACLUndefinedID
+		}
+		entries = append(entries, e)
+	}
+	return entries, validACL(entries)
+}
@@ -0,0 +1,32 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// ErrorSymlinkChmod is returned by Lchmod, and by Fchmodat with
+// AtSymlinkNoFollow, when the named file is a symbolic link.  Linux does not
+// store permissions on symbolic links and refuses to change them.
+var ErrorSymlinkChmod = errors.New("Cannot Chmod Symlink")
+
+// Fchmod changes the permissions on an already opened file to the Mode
+// permission bits, like Chmod but without resolving a path again.
+func Fchmod(f *os.File, m Mode) error {
+	return f.Chmod(fs.FileMode(m)&0777 | fs.FileMode(m)&06000<<12 | fs.FileMode(m)&01000<<11)
+}
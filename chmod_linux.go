@@ -0,0 +1,83 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"io/fs"
+	"strconv"
+	"syscall"
+)
+
+// Flags and the special directory descriptor for Fchmodat.
+const (
+	AtFDCWD           = -0x64 /* use the current working directory */
+	AtSymlinkNoFollow = 0x100 /* do not follow a trailing symbolic link */
+
+	oPath = 0x200000 /* O_PATH, the same value on all Go Linux ports */
+)
+
+// Fchmodat changes the permissions of name relative to the directory opened
+// as dirfd, or relative to the working directory when dirfd is AtFDCWD.  When
+// flags holds AtSymlinkNoFollow a trailing symbolic link is not followed and
+// ErrorSymlinkChmod is returned if name is one.
+//
+// Kernels before 6.6 lack fchmodat2(2), so the no-follow case then falls back
+// to opening name with O_PATH and changing it through /proc/self/fd, as glibc
+// does.
+func Fchmodat(dirfd int, name string, m Mode, flags int) error {
+	return fchmodat("fchmodat", dirfd, name, m, flags)
+}
+
+// Lchmod changes the permissions of name without following a trailing
+// symbolic link.  On Linux this fails with ErrorSymlinkChmod whenever name is
+// a symbolic link, which can be tested with errors.Is.
+func Lchmod(name string, m Mode) error {
+	return fchmodat("lchmod", AtFDCWD, name, m, AtSymlinkNoFollow)
+}
+
+func fchmodat(op string, dirfd int, name string, m Mode, flags int) error {
+	err := syscall.Fchmodat(dirfd, name, uint32(m.Perm()), flags)
+	if err == syscall.EOPNOTSUPP && flags&AtSymlinkNoFollow != 0 {
+		err = pathChmod(dirfd, name, m)
+	}
+	if err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	return nil
+}
+
+// pathChmod changes the mode through an O_PATH descriptor, so the file
+// checked is the file changed.
+func pathChmod(dirfd int, name string, m Mode) error {
+	fd, err := syscall.Openat(dirfd, name, oPath|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(fd)
+
+	var st syscall.Stat_t
+	if err = syscall.Fstat(fd, &st); err != nil {
+		return err
+	}
+	if Mode(st.Mode)&ModeTypeMask == ModeSymlink {
+		return ErrorSymlinkChmod
+	}
+	err = syscall.Chmod("/proc/self/fd/"+strconv.Itoa(fd), uint32(m.Perm()))
+	if err == syscall.ENOENT {
+		// /proc is not mounted
+		return syscall.EOPNOTSUPP
+	}
+	return err
+}
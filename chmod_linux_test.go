@@ -0,0 +1,55 @@
+package unixmode_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleLchmod() {
+	dir, _ := os.MkdirTemp("", "lchmod")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "file")
+	link := filepath.Join(dir, "link")
+	os.WriteFile(file, nil, 0600)
+	os.Symlink("file", link)
+
+	err := unixmode.Lchmod(link, 0644)
+	fmt.Println(errors.Is(err, unixmode.ErrorSymlinkChmod), err != nil)
+
+	err = unixmode.Lchmod(file, 04750)
+	st, _ := os.Lstat(file)
+	fmt.Printf("%v %q\n", err, unixmode.FileModeString(st.Mode()))
+	// Output:
+	// true true
+	// <nil> "-rwsr-x--- "
+}
+
+func ExampleFchmod() {
+	f, _ := os.CreateTemp("", "fchmod")
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	err := unixmode.Fchmod(f, 01640)
+	st, _ := f.Stat()
+	fmt.Printf("%v %q\n", err, unixmode.FileModeString(st.Mode()))
+	// Output:
+	// <nil> "-rw-r----T "
+}
+
+func ExampleFchmodat() {
+	dir, _ := os.Open(os.TempDir())
+	defer dir.Close()
+	f, _ := os.CreateTemp("", "fchmodat")
+	f.Close()
+	defer os.Remove(f.Name())
+
+	err := unixmode.Fchmodat(int(dir.Fd()), filepath.Base(f.Name()), 0604, 0)
+	st, _ := os.Stat(f.Name())
+	fmt.Println(err, unixmode.FileModePermString(st.Mode()))
+	// Output:
+	// <nil> rw----r--
+}
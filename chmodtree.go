@@ -0,0 +1,196 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TreeOptions controls how ChmodTree walks and changes a directory tree.
+type TreeOptions struct {
+	// DirMode and FileMode are the permission bits to set on directories and
+	// on all other entries.  When the matching DirExpr or FileExpr is set, it
+	// is applied to the current mode instead.  An entry with neither a Mode
+	// nor an expression is left alone, so a zero TreeOptions changes nothing.
+	DirMode, FileMode *Mode
+	DirExpr, FileExpr *Symbolic
+
+	// FollowSymlinks descends into symbolic links which point to directories
+	// and changes the target of every symbolic link met.  When false, symbolic
+	// links are skipped, as chmod(2) cannot change the mode of a link itself.
+	FollowSymlinks bool
+
+	// Include and Exclude are filepath.Match patterns tested against both the
+	// base name and the path relative to root.  When Include is non-empty an
+	// entry must match one of them to be changed.  An excluded directory is
+	// not descended into.
+	Include, Exclude []string
+
+	// Changed, when set, is called for each entry whose mode was altered.
+	Changed func(path string, from, to Mode)
+
+	// DryRun reports changes through Changed without calling chmod.
+	DryRun bool
+}
+
+// TreeError collects every error met by ChmodTree.
+type TreeError struct {
+	Errors []*fs.PathError
+}
+
+func (e *TreeError) Error() string {
+	var s []string
+	for _, pe := range e.Errors {
+		s = append(s, pe.Error())
+	}
+	return fmt.Sprintf("%d errors: %s", len(e.Errors), strings.Join(s, "; "))
+}
+
+// ChmodTree walks the tree rooted at root and sets the modes given in opts.
+// The root itself is always followed when it is a symbolic link.  Errors on
+// individual entries do not stop the walk, they are collected and returned
+// together as a *TreeError.
+func ChmodTree(root string, opts TreeOptions) error {
+	for _, pat := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := filepath.Match(pat, ""); err != nil {
+			return fmt.Errorf("pattern %q: %w", pat, err)
+		}
+	}
+	t := &treeWalker{opts: &opts, root: root, visited: make(map[string]bool)}
+	t.walk(root, true)
+	if len(t.errs) > 0 {
+		return &TreeError{Errors: t.errs}
+	}
+	return nil
+}
+
+type treeWalker struct {
+	opts    *TreeOptions
+	root    string
+	errs    []*fs.PathError
+	visited map[string]bool // directories entered while following symlinks
+}
+
+func (t *treeWalker) fail(op, path string, err error) {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		t.errs = append(t.errs, pe)
+		return
+	}
+	t.errs = append(t.errs, &fs.PathError{Op: op, Path: path, Err: err})
+}
+
+func (t *treeWalker) walk(path string, isRoot bool) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		t.fail("lstat", path, err)
+		return
+	}
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		if !t.opts.FollowSymlinks && !isRoot {
+			return
+		}
+		if fi, err = os.Stat(path); err != nil {
+			t.fail("stat", path, err)
+			return
+		}
+	}
+
+	if t.excluded(path) {
+		return
+	}
+	if t.included(path) {
+		t.chmod(path, fi)
+	}
+
+	if !fi.IsDir() {
+		return
+	}
+	if t.opts.FollowSymlinks {
+		// Guard against symbolic link loops
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			t.fail("readlink", path, err)
+			return
+		}
+		if t.visited[real] {
+			return
+		}
+		t.visited[real] = true
+	}
+	ents, err := os.ReadDir(path)
+	if err != nil {
+		t.fail("readdir", path, err)
+	}
+	for _, ent := range ents {
+		t.walk(filepath.Join(path, ent.Name()), false)
+	}
+}
+
+func (t *treeWalker) chmod(path string, fi fs.FileInfo) {
+	from := New(fi.Mode())
+	to := from
+	mode, expr := t.opts.FileMode, t.opts.FileExpr
+	if fi.IsDir() {
+		mode, expr = t.opts.DirMode, t.opts.DirExpr
+	}
+	switch {
+	case expr != nil:
+		to = expr.Apply(from)
+	case mode != nil:
+		to = from.Type() | mode.Perm()
+	}
+	if to == from {
+		return
+	}
+	if !t.opts.DryRun {
+		if err := Chmod(path, to); err != nil {
+			t.fail("chmod", path, err)
+			return
+		}
+	}
+	if t.opts.Changed != nil {
+		t.opts.Changed(path, from, to)
+	}
+}
+
+func (t *treeWalker) match(pats []string, path string) bool {
+	rel, err := filepath.Rel(t.root, path)
+	if err != nil {
+		rel = path
+	}
+	for _, pat := range pats {
+		if ok, _ := filepath.Match(pat, filepath.Base(path)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pat, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *treeWalker) excluded(path string) bool {
+	return len(t.opts.Exclude) > 0 && t.match(t.opts.Exclude, path)
+}
+
+func (t *treeWalker) included(path string) bool {
+	return len(t.opts.Include) == 0 || t.match(t.opts.Include, path)
+}
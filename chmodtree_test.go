@@ -0,0 +1,46 @@
+package unixmode_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleChmodTree() {
+	root, _ := os.MkdirTemp("", "chmodtree")
+	defer os.RemoveAll(root)
+	os.MkdirAll(filepath.Join(root, "bin"), 0700)
+	os.MkdirAll(filepath.Join(root, ".git"), 0700)
+	os.WriteFile(filepath.Join(root, "bin", "run"), nil, 0700)
+	os.WriteFile(filepath.Join(root, "README"), nil, 0600)
+	os.WriteFile(filepath.Join(root, ".git", "HEAD"), nil, 0600)
+	os.Symlink("README", filepath.Join(root, "link"))
+	os.Chmod(root, 0755)
+
+	dirMode := unixmode.Mode(0755)
+	fileExpr, _ := unixmode.ParseSymbolic("go=u-w")
+	err := unixmode.ChmodTree(root, unixmode.TreeOptions{
+		DirMode:  &dirMode,
+		FileExpr: fileExpr,
+		Exclude:  []string{".git"},
+		Changed: func(path string, from, to unixmode.Mode) {
+			rel, _ := filepath.Rel(root, path)
+			fmt.Printf("%-6s %s -> %s\n", rel, from.PermString(), to.PermString())
+		},
+	})
+	fmt.Println("err:", err)
+	// Output:
+	// README rw------- -> rw-r--r--
+	// bin    rwx------ -> rwxr-xr-x
+	// bin/run rwx------ -> rwxr-xr-x
+	// err: <nil>
+}
+
+func ExampleChmodTree_errors() {
+	err := unixmode.ChmodTree("/nonexistent/path", unixmode.TreeOptions{})
+	fmt.Println(err)
+	// Output:
+	// 1 errors: lstat /nonexistent/path: no such file or directory
+}
@@ -0,0 +1,425 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CpioFormat is one of the cpio(5) header formats.
+type CpioFormat int
+
+const (
+	CpioNewc CpioFormat = iota // "070701" SVR4 hex format, used by initramfs
+	CpioCRC                    // "070702" SVR4 hex format with a data checksum, used by RPM
+	CpioODC                    // "070707" POSIX.1 portable octal format
+)
+
+// CpioTrailer is the name of the entry which ends every cpio archive.
+const CpioTrailer = "TRAILER!!!"
+
+var (
+	ErrorCpio         = errors.New("Invalid Cpio Header")
+	ErrorCpioChecksum = errors.New("Cpio Checksum Mismatch")
+	ErrorCpioWrite    = errors.New("Cpio Write Out Of Order")
+)
+
+// cpioMaxName is the largest name size accepted, PATH_MAX and the NUL.
+const cpioMaxName = 4097
+
+var cpioMagic = [...]string{CpioNewc: "070701", CpioCRC: "070702", CpioODC: "070707"}
+
+func (f CpioFormat) String() string {
+	switch f {
+	case CpioNewc:
+		return "newc"
+	case CpioCRC:
+		return "crc"
+	case CpioODC:
+		return "odc"
+	}
+	return "CpioFormat(" + strconv.Itoa(int(f)) + ")"
+}
+
+// A CpioHeader is one cpio entry.  The FileStat fields map one to one onto
+// the header, with c_mode as the raw st_mode.  Dev and Rdev use the Linux
+// encoding of FileStat, the SVR4 formats split them into major and minor
+// fields while odc keeps the 16 bit major<<8|minor form.  Rdev is only
+// recorded for ModeDevice and ModeCharDevice entries.
+type CpioHeader struct {
+	FileStat
+	Format CpioFormat
+	Name   string
+	Check  uint32 // sum of the data bytes, for CpioCRC
+}
+
+// CpioChecksum returns the CpioCRC checksum of the data, which despite the
+// name is the 32 bit sum of the bytes.
+func CpioChecksum(data []byte) uint32 {
+	var sum uint32
+	for _, b := range data {
+		sum += uint32(b)
+	}
+	return sum
+}
+
+// cpioPad returns the padding to align n to 4 bytes in the SVR4 formats.
+func cpioPad(f CpioFormat, n int64) int64 {
+	if f == CpioODC {
+		return 0
+	}
+	return -n & 3
+}
+
+// A CpioReader reads a cpio archive entry by entry, like tar.Reader.
+type CpioReader struct {
+	r      io.Reader
+	h      *CpioHeader
+	remain int64 // data bytes left in the current entry
+	pad    int64 // padding after the current entry
+	sum    uint32
+	err    error
+}
+
+// NewCpioReader returns a CpioReader reading from r.
+func NewCpioReader(r io.Reader) *CpioReader {
+	return &CpioReader{r: r}
+}
+
+// Next advances to the next entry, skipping any unread data.  The
+// CpioTrailer entry is not returned, io.EOF is returned in its place.
+func (cr *CpioReader) Next() (*CpioHeader, error) {
+	if cr.err != nil {
+		return nil, cr.err
+	}
+	if _, err := io.CopyN(io.Discard, cr, cr.remain); err != nil {
+		return nil, cr.fail(err)
+	}
+	if _, err := io.CopyN(io.Discard, cr.r, cr.pad); err != nil {
+		return nil, cr.fail(err)
+	}
+
+	var magic [6]byte
+	if _, err := io.ReadFull(cr.r, magic[:]); err != nil {
+		return nil, cr.fail(err)
+	}
+	h := &CpioHeader{Format: -1}
+	for f, m := range cpioMagic {
+		if string(magic[:]) == m {
+			h.Format = CpioFormat(f)
+		}
+	}
+	if h.Format < 0 {
+		return nil, cr.fail(fmt.Errorf("%w: bad magic %q", ErrorCpio, magic[:]))
+	}
+	var namesize int64
+	var err error
+	if h.Format == CpioODC {
+		namesize, err = cr.readODC(h)
+	} else {
+		namesize, err = cr.readNewc(h)
+	}
+	if err != nil {
+		return nil, cr.fail(err)
+	}
+
+	if namesize > cpioMaxName {
+		return nil, cr.fail(fmt.Errorf("%w: name size %d exceeds %d", ErrorCpio, namesize, cpioMaxName))
+	}
+	name := make([]byte, namesize)
+	if _, err := io.ReadFull(cr.r, name); err != nil {
+		return nil, cr.fail(err)
+	}
+	if namesize == 0 || name[namesize-1] != 0 {
+		return nil, cr.fail(fmt.Errorf("%w: name is not NUL terminated", ErrorCpio))
+	}
+	h.Name = string(name[:namesize-1])
+	hdrsize := int64(110)
+	if h.Format == CpioODC {
+		hdrsize = 76
+	}
+	if _, err := io.CopyN(io.Discard, cr.r, cpioPad(h.Format, hdrsize+namesize)); err != nil {
+		return nil, cr.fail(err)
+	}
+	if h.Name == CpioTrailer {
+		cr.err = io.EOF
+		return nil, io.EOF
+	}
+	if !h.Mode.validType() {
+		return nil, cr.fail(fmt.Errorf("%w: %q has type bits %06o", ErrorModeType, h.Name, h.Mode&ModeTypeMask))
+	}
+	if t := h.Mode.Type(); t != ModeDevice && t != ModeCharDevice {
+		h.Rdev = 0
+	}
+	cr.h, cr.remain, cr.pad, cr.sum = h, h.Size, cpioPad(h.Format, h.Size), 0
+	return h, nil
+}
+
+func (cr *CpioReader) fail(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	cr.err = err
+	return err
+}
+
+// readFields reads len(widths) numbers in the given base.
+func (cr *CpioReader) readFields(base int, widths ...int) ([]uint64, error) {
+	n := 0
+	for _, w := range widths {
+		n += w
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(cr.r, buf); err != nil {
+		return nil, err
+	}
+	v := make([]uint64, len(widths))
+	for i, w := range widths {
+		var err error
+		if v[i], err = strconv.ParseUint(string(buf[:w]), base, 64); err != nil {
+			return nil, fmt.Errorf("%w: field %q is not base %d", ErrorCpio, buf[:w], base)
+		}
+		buf = buf[w:]
+	}
+	return v, nil
+}
+
+func (cr *CpioReader) readNewc(h *CpioHeader) (int64, error) {
+	v, err := cr.readFields(16, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8)
+	if err != nil {
+		return 0, err
+	}
+	if v[1] > 0177777 {
+		return 0, fmt.Errorf("%w: mode %o exceeds %o", ErrorModeRange, v[1], 0177777)
+	}
+	h.Ino, h.Mode, h.Uid, h.Gid, h.Nlink = v[0], Mode(v[1]), uint32(v[2]), uint32(v[3]), v[4]
+	h.Mtime = time.Unix(int64(v[5]), 0)
+	h.Size = int64(v[6])
+	h.Dev = MakeDev(uint32(v[7]), uint32(v[8]))
+	h.Rdev = MakeDev(uint32(v[9]), uint32(v[10]))
+	h.Check = uint32(v[12])
+	return int64(v[11]), nil
+}
+
+func (cr *CpioReader) readODC(h *CpioHeader) (int64, error) {
+	v, err := cr.readFields(8, 6, 6, 6, 6, 6, 6, 6, 11, 6, 11)
+	if err != nil {
+		return 0, err
+	}
+	if v[2] > 0177777 {
+		return 0, fmt.Errorf("%w: mode %o exceeds %o", ErrorModeRange, v[2], 0177777)
+	}
+	h.Dev, h.Ino, h.Mode, h.Uid, h.Gid, h.Nlink, h.Rdev = v[0], v[1], Mode(v[2]), uint32(v[3]), uint32(v[4]), v[5], v[6]
+	h.Mtime = time.Unix(int64(v[7]), 0)
+	h.Size = int64(v[9])
+	return int64(v[8]), nil
+}
+
+// Read reads the data of the current entry.  For CpioCRC entries the
+// checksum is verified once all the data is read.
+func (cr *CpioReader) Read(p []byte) (int, error) {
+	if cr.remain == 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > cr.remain {
+		p = p[:cr.remain]
+	}
+	n, err := cr.r.Read(p)
+	cr.remain -= int64(n)
+	if cr.h.Format == CpioCRC {
+		cr.sum += CpioChecksum(p[:n])
+		if cr.remain == 0 && cr.sum != cr.h.Check {
+			return n, fmt.Errorf("%w: %q sums to %08x, header has %08x", ErrorCpioChecksum, cr.h.Name, cr.sum, cr.h.Check)
+		}
+	}
+	if err == io.EOF && cr.remain > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// A CpioWriter writes a cpio archive, like tar.Writer.  Each entry is written
+// in its own Format, and Close writes the CpioTrailer entry in the format of
+// the last one.  CpioCRC entries are written with the Check given in the
+// header, which CpioChecksum computes from the data.
+type CpioWriter struct {
+	w      io.Writer
+	format CpioFormat
+	remain int64
+	pad    int64
+	err    error
+}
+
+// NewCpioWriter returns a CpioWriter writing to w.
+func NewCpioWriter(w io.Writer) *CpioWriter {
+	return &CpioWriter{w: w}
+}
+
+// WriteHeader writes h and prepares to accept h.Size bytes of data.
+func (cw *CpioWriter) WriteHeader(h *CpioHeader) error {
+	if err := cw.flush(); err != nil {
+		return err
+	}
+	if !h.Mode.validType() {
+		return fmt.Errorf("%w: %q has type bits %06o", ErrorModeType, h.Name, h.Mode&ModeTypeMask)
+	}
+	if h.Size < 0 {
+		return fmt.Errorf("%w: %q has size %d", ErrorCpio, h.Name, h.Size)
+	}
+	if err := cw.writeHeader(h); err != nil {
+		return err
+	}
+	cw.format, cw.remain, cw.pad = h.Format, h.Size, cpioPad(h.Format, h.Size)
+	return nil
+}
+
+func (cw *CpioWriter) writeHeader(h *CpioHeader) error {
+	var mtime int64
+	if !h.Mtime.IsZero() {
+		mtime = h.Mtime.Unix()
+	}
+	rdev := h.Rdev
+	if t := h.Mode.Type(); t != ModeDevice && t != ModeCharDevice {
+		rdev = 0
+	}
+	namesize := len(h.Name) + 1
+
+	var buf []byte
+	field := func(v uint64, base, width int) {
+		s := strconv.FormatUint(v, base)
+		for i := len(s); i < width; i++ {
+			buf = append(buf, '0')
+		}
+		buf = append(buf, strings.ToUpper(s)...) // as gen_init_cpio writes them
+	}
+	fits := func(what string, v uint64, base, width int) error {
+		if len(strconv.FormatUint(v, base)) > width {
+			return fmt.Errorf("%w: %q %s %d does not fit the %v format", ErrorCpio, h.Name, what, v, h.Format)
+		}
+		return nil
+	}
+
+	switch h.Format {
+	case CpioNewc, CpioCRC:
+		for _, f := range []struct {
+			what string
+			v    uint64
+		}{{"inode", h.Ino}, {"nlink", h.Nlink}, {"mtime", uint64(mtime)}, {"size", uint64(h.Size)}} {
+			if err := fits(f.what, f.v, 16, 8); err != nil {
+				return err
+			}
+		}
+		check := h.Check
+		if h.Format == CpioNewc {
+			check = 0
+		}
+		buf = append(buf, cpioMagic[h.Format]...)
+		for _, v := range []uint64{
+			h.Ino, uint64(h.Mode), uint64(h.Uid), uint64(h.Gid), h.Nlink,
+			uint64(mtime), uint64(h.Size),
+			uint64(DevMajor(h.Dev)), uint64(DevMinor(h.Dev)),
+			uint64(DevMajor(rdev)), uint64(DevMinor(rdev)),
+			uint64(namesize), uint64(check),
+		} {
+			field(v, 16, 8)
+		}
+	case CpioODC:
+		for _, f := range []struct {
+			what  string
+			v     uint64
+			width int
+		}{
+			{"device", h.Dev, 6}, {"inode", h.Ino, 6}, {"uid", uint64(h.Uid), 6},
+			{"gid", uint64(h.Gid), 6}, {"nlink", h.Nlink, 6}, {"rdev", rdev, 6},
+			{"mtime", uint64(mtime), 11}, {"name size", uint64(namesize), 6},
+			{"size", uint64(h.Size), 11},
+		} {
+			if err := fits(f.what, f.v, 8, f.width); err != nil {
+				return err
+			}
+		}
+		buf = append(buf, cpioMagic[h.Format]...)
+		field(h.Dev, 8, 6)
+		field(h.Ino, 8, 6)
+		field(uint64(h.Mode), 8, 6)
+		field(uint64(h.Uid), 8, 6)
+		field(uint64(h.Gid), 8, 6)
+		field(h.Nlink, 8, 6)
+		field(rdev, 8, 6)
+		field(uint64(mtime), 8, 11)
+		field(uint64(namesize), 8, 6)
+		field(uint64(h.Size), 8, 11)
+	default:
+		return fmt.Errorf("%w: unknown format %v", ErrorCpio, h.Format)
+	}
+	buf = append(buf, h.Name...)
+	buf = append(buf, 0)
+	buf = append(buf, make([]byte, cpioPad(h.Format, int64(len(buf))))...)
+	if _, err := cw.w.Write(buf); err != nil {
+		cw.err = err
+		return err
+	}
+	return nil
+}
+
+// Write writes data for the current entry, up to the header Size.
+func (cw *CpioWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	if int64(len(p)) > cw.remain {
+		return 0, fmt.Errorf("%w: %d bytes past the header size", ErrorCpioWrite, int64(len(p))-cw.remain)
+	}
+	n, err := cw.w.Write(p)
+	cw.remain -= int64(n)
+	if err != nil {
+		cw.err = err
+	}
+	return n, err
+}
+
+// flush finishes the current entry with its padding.
+func (cw *CpioWriter) flush() error {
+	if cw.err != nil {
+		return cw.err
+	}
+	if cw.remain > 0 {
+		return fmt.Errorf("%w: %d bytes of data missing", ErrorCpioWrite, cw.remain)
+	}
+	if _, err := cw.w.Write(make([]byte, cw.pad)); err != nil {
+		cw.err = err
+		return err
+	}
+	cw.pad = 0
+	return nil
+}
+
+// Close writes the CpioTrailer entry.  It does not close the underlying
+// writer.
+func (cw *CpioWriter) Close() error {
+	if err := cw.flush(); err != nil {
+		return err
+	}
+	err := cw.writeHeader(&CpioHeader{Format: cw.format, Name: CpioTrailer, FileStat: FileStat{Nlink: 1}})
+	if err == nil {
+		cw.err = ErrorCpioWrite
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package unixmode_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleCpioReader() {
+	var buf bytes.Buffer
+	cw := unixmode.NewCpioWriter(&buf)
+	script := []byte("#!/bin/sh\nexec /sbin/init\n")
+	for _, e := range []struct {
+		h    unixmode.CpioHeader
+		data []byte
+	}{
+		{h: unixmode.CpioHeader{Name: "dev", FileStat: unixmode.FileStat{Mode: unixmode.ModeDir | 0755, Nlink: 2}}},
+		{h: unixmode.CpioHeader{Name: "dev/console", FileStat: unixmode.FileStat{
+			Mode: unixmode.ModeCharDevice | 0600, Nlink: 1, Rdev: unixmode.MakeDev(5, 1)}}},
+		{h: unixmode.CpioHeader{Name: "init", FileStat: unixmode.FileStat{
+			Mode: unixmode.ModeRegular | 0755, Nlink: 1, Size: int64(len(script))}}, data: script},
+	} {
+		cw.WriteHeader(&e.h)
+		cw.Write(e.data)
+	}
+	cw.Close()
+	fmt.Printf("%q\n", buf.Bytes()[:110])
+
+	cr := unixmode.NewCpioReader(&buf)
+	for {
+		h, err := cr.Next()
+		if err != nil {
+			fmt.Println(err)
+			break
+		}
+		data, _ := io.ReadAll(cr)
+		fmt.Printf("%v %c%s %d,%d %-11s %q\n", h.Format, h.Mode.TypeLetter(), h.Mode.PermString(),
+			unixmode.DevMajor(h.Rdev), unixmode.DevMinor(h.Rdev), h.Name, data)
+	}
+	// Output:
+	// "07070100000000000041ED0000000000000000000000020000000000000000000000000000000000000000000000000000000400000000"
+	// newc drwxr-xr-x 0,0 dev         ""
+	// newc crw------- 5,1 dev/console ""
+	// newc -rwxr-xr-x 0,0 init        "#!/bin/sh\nexec /sbin/init\n"
+	// EOF
+}
+
+func ExampleCpioWriter_rewrite() {
+	// Build a crc archive, then make every file in it read-only while
+	// copying it to an odc archive.
+	var crc, odc bytes.Buffer
+	data := []byte("secret")
+	cw := unixmode.NewCpioWriter(&crc)
+	cw.WriteHeader(&unixmode.CpioHeader{Format: unixmode.CpioCRC, Name: "etc/shadow",
+		Check: unixmode.CpioChecksum(data), FileStat: unixmode.FileStat{
+			Mode: unixmode.ModeRegular | 0644, Nlink: 1, Size: int64(len(data))}})
+	cw.Write(data)
+	cw.Close()
+
+	cr := unixmode.NewCpioReader(&crc)
+	cw = unixmode.NewCpioWriter(&odc)
+	ro, _ := unixmode.ParseSymbolic("a-w")
+	for {
+		h, err := cr.Next()
+		if err != nil {
+			break
+		}
+		h.Format = unixmode.CpioODC
+		h.Mode = ro.Apply(h.Mode)
+		cw.WriteHeader(h)
+		if _, err := io.Copy(cw, cr); err != nil {
+			fmt.Println(err)
+		}
+	}
+	cw.Close()
+
+	h, _ := unixmode.NewCpioReader(&odc).Next()
+	fmt.Println(h.Format, h.Name, h.Mode.Octal(unixmode.OctalFull), h.Size)
+	// Output:
+	// odc etc/shadow 100444 6
+}
+
+func ExampleCpioReader_invalid() {
+	// A newc header claiming a 4 GiB name
+	hdr := "070701" + strings.Repeat("00000000", 11) + "FFFFFFFF" + "00000000"
+	_, err := unixmode.NewCpioReader(strings.NewReader(hdr)).Next()
+	fmt.Println(err, errors.Is(err, unixmode.ErrorCpio))
+	// Output:
+	// Invalid Cpio Header: name size 4294967295 exceeds 4097 true
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import "io/fs"
+
+// An ExtendedMode is a Mode widened to 32 bits to carry the fs.FileMode flags
+// which have no place in a POSIX st_mode.  The lower 16 bits are the Mode,
+// the flags sit above them.  Converting a fs.FileMode with NewExtended and
+// back with FileMode returns the same value for every fs.FileMode with a
+// valid type, except the undefined bits 9 to 18 which are dropped.
+type ExtendedMode uint32
+
+const (
+	ExtAppend    ExtendedMode = 1 << 16 /* fs.ModeAppend: append-only */
+	ExtExclusive ExtendedMode = 1 << 17 /* fs.ModeExclusive: exclusive use */
+	ExtTemporary ExtendedMode = 1 << 18 /* fs.ModeTemporary: temporary file (Plan 9) */
+	ExtIrregular ExtendedMode = 1 << 19 /* fs.ModeIrregular: type not known to Go */
+
+	ExtFlagMask = ExtAppend | ExtExclusive | ExtTemporary | ExtIrregular
+)
+
+// NewExtended creates an ExtendedMode from a fs.FileMode, keeping every flag.
+func NewExtended(m fs.FileMode) ExtendedMode {
+	e := ExtendedMode(New(m))
+	if m&fs.ModeType == fs.ModeIrregular {
+		// New has no type for this, record only the flag
+		e &^= ExtendedMode(ModeTypeMask)
+	}
+	if m&fs.ModeAppend != 0 {
+		e |= ExtAppend
+	}
+	if m&fs.ModeExclusive != 0 {
+		e |= ExtExclusive
+	}
+	if m&fs.ModeTemporary != 0 {
+		e |= ExtTemporary
+	}
+	if m&fs.ModeIrregular != 0 {
+		e |= ExtIrregular
+	}
+	return e
+}
+
+// Extend widens a Mode into an ExtendedMode with no flags set.
+func (m Mode) Extend() ExtendedMode {
+	return ExtendedMode(m)
+}
+
+// FileMode converts back to a fs.FileMode, including the flags.
+func (e ExtendedMode) FileMode() fs.FileMode {
+	out := e.Mode().FileMode()
+	if e&ExtIrregular != 0 {
+		out |= fs.ModeIrregular
+	}
+	if e&ExtAppend != 0 {
+		out |= fs.ModeAppend
+	}
+	if e&ExtExclusive != 0 {
+		out |= fs.ModeExclusive
+	}
+	if e&ExtTemporary != 0 {
+		out |= fs.ModeTemporary
+	}
+	return out
+}
+
+// Mode returns the POSIX st_mode part.
+func (e ExtendedMode) Mode() Mode {
+	return Mode(e)
+}
+
+// Flags returns the flag bits, e & ExtFlagMask.
+func (e ExtendedMode) Flags() ExtendedMode {
+	return e & ExtFlagMask
+}
+
+// Perm returns the Unix permission bits, as Mode.Perm.
+func (e ExtendedMode) Perm() Mode {
+	return e.Mode().Perm()
+}
+
+// Type returns the type bits, as Mode.Type.
+func (e ExtendedMode) Type() Mode {
+	return e.Mode().Type()
+}
+
+// PermString returns the permission bits as Mode.PermString.
+func (e ExtendedMode) PermString() string {
+	return e.Mode().PermString()
+}
+
+// String returns the Mode.String form with the 11th character replaced by the
+// flag letters of fs.FileMode.String, "a", "l", "T", and "?", when any are
+// set, like "-rw-r--r--a".
+func (e ExtendedMode) String() string {
+	s := e.Mode().String()
+	if e.Flags() == 0 {
+		return s
+	}
+	buf := []byte(s[:10])
+	for _, f := range []struct {
+		bit ExtendedMode
+		c   byte
+	}{{ExtAppend, 'a'}, {ExtExclusive, 'l'}, {ExtTemporary, 'T'}, {ExtIrregular, '?'}} {
+		if e&f.bit != 0 {
+			buf = append(buf, f.c)
+		}
+	}
+	return string(buf)
+}
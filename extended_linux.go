@@ -0,0 +1,90 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// ChmodExtended sets the permission bits of a file and maps the flags of e
+// onto Linux inode attributes:
+//
+// - ExtAppend sets the append-only attribute, and its absence clears it.
+// Changing the attribute needs CAP_LINUX_IMMUTABLE.
+//
+// - ExtExclusive and ExtTemporary have no Linux counterpart and are ignored.
+//
+// - ExtIrregular and the type bits describe the file and cannot be written.
+//
+// When statx(2) shows the file is neither append-only nor immutable and
+// ExtAppend is not set, this is a plain Chmod.  Otherwise the file is opened first,
+// while its old permissions still allow it, the append-only and immutable
+// attributes, which make chmod(2) fail, are lifted for the mode change, and
+// the wanted attributes are put back afterwards.  A file system without
+// inode attributes only fails when ExtAppend is set.
+func ChmodExtended(name string, e ExtendedMode) error {
+	const held = uint32(FlagAppend | FlagImmutable)
+	const heldAttr = StatxAttrAppend | StatxAttrImmutable
+	wantAppend := e&ExtAppend != 0
+	if sx, err := Statx(name, 0); err == nil && sx.AttributesMask&heldAttr == heldAttr {
+		if sx.Attributes&heldAttr == 0 && !wantAppend {
+			return Chmod(name, e.Mode())
+		}
+	}
+
+	f, err := os.OpenFile(name, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var flags uint32
+	if err = fileControl(f, func(fd int) (err error) {
+		flags, err = ioctlGetFlags(fd)
+		return
+	}); err != nil {
+		if wantAppend {
+			return &fs.PathError{Op: "getflags", Path: name, Err: flagsError(err)}
+		}
+		return Fchmod(f, e.Mode())
+	}
+	want := flags &^ uint32(FlagAppend)
+	if wantAppend {
+		want |= uint32(FlagAppend)
+	}
+
+	setFlags := func(v uint32) error {
+		return fileControl(f, func(fd int) error { return ioctlSetFlags(fd, v) })
+	}
+	if flags&held != 0 {
+		if err = setFlags(flags &^ held); err != nil {
+			return &fs.PathError{Op: "setflags", Path: name, Err: err}
+		}
+	}
+	if err = Fchmod(f, e.Mode()); err != nil {
+		if flags&held != 0 {
+			setFlags(flags)
+		}
+		return err
+	}
+	if want != flags&^held {
+		if err = setFlags(want); err != nil {
+			return &fs.PathError{Op: "setflags", Path: name, Err: err}
+		}
+	}
+	return nil
+}
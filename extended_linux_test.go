@@ -0,0 +1,47 @@
+package unixmode_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func TestChmodExtended(t *testing.T) {
+	f, err := os.CreateTemp("", "extended")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	defer unixmode.ChmodExtended(f.Name(), 0600)
+
+	err = unixmode.ChmodExtended(f.Name(), unixmode.ExtAppend|0644)
+	if errors.Is(err, unixmode.ErrorInodeFlagsUnsupported) || errors.Is(err, syscall.EPERM) {
+		t.Skip("cannot set the append-only attribute:", err)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+
+	// An append-only file refuses chmod(2), the attribute must be lifted
+	for _, e := range []unixmode.ExtendedMode{
+		unixmode.ExtAppend | 0640,
+		unixmode.ExtAppend | 0200,
+		0200,
+		0644,
+	} {
+		if err := unixmode.ChmodExtended(f.Name(), e); err != nil {
+			t.Fatalf("ChmodExtended(%v): %v", e, err)
+		}
+		flags, err := unixmode.GetFlags(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		fi, _ := os.Stat(f.Name())
+		if fi.Mode().Perm() != e.Perm().FileMode() || (flags&unixmode.FlagAppend != 0) != (e&unixmode.ExtAppend != 0) {
+			t.Errorf("ChmodExtended(%v) left mode %v flags %v", e, fi.Mode(), flags)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package unixmode_test
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleNewExtended() {
+	fm := fs.ModeAppend | fs.ModeTemporary | fs.ModeSetuid | 0755
+	e := unixmode.NewExtended(fm)
+	fmt.Printf("%q %v %v\n", e.String(), e.Perm().Octal(unixmode.OctalChmod), e.FileMode() == fm)
+	fmt.Printf("%q\n", unixmode.New(fm).FileMode())
+	// Output:
+	// "-rwsr-xr-xaT" 4755 true
+	// "urwxr-xr-x"
+}
+
+func TestExtendedRoundTrip(t *testing.T) {
+	types := []fs.FileMode{0, fs.ModeDir, fs.ModeSymlink, fs.ModeNamedPipe, fs.ModeSocket,
+		fs.ModeDevice, fs.ModeDevice | fs.ModeCharDevice, fs.ModeIrregular}
+	flags := []fs.FileMode{fs.ModeAppend, fs.ModeExclusive, fs.ModeTemporary,
+		fs.ModeSetuid, fs.ModeSetgid, fs.ModeSticky}
+	for _, typ := range types {
+		for f := 0; f < 1<<len(flags); f++ {
+			for p := fs.FileMode(0); p <= 0777; p++ {
+				m := typ | p
+				for i, flag := range flags {
+					if f&(1<<i) != 0 {
+						m |= flag
+					}
+				}
+				if got := unixmode.NewExtended(m).FileMode(); got != m {
+					t.Fatalf("NewExtended(%v).FileMode() = %v", m, got)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import "io/fs"
+
+// Extras describe what a file carries beyond its mode, shown by ls -l in the
+// character after the permissions.
+type Extras uint8
+
+const (
+	ExtraACL             Extras = 1 << iota /* an extended ACL, shown as '+' */
+	ExtraSecurityContext                    /* a security context, shown as '.' */
+)
+
+// Letter returns the character GNU ls prints for the Extras: '+' for an
+// extended ACL, '.' for a security context alone, and ' ' otherwise.
+func (x Extras) Letter() byte {
+	switch {
+	case x&ExtraACL != 0:
+		return '+'
+	case x&ExtraSecurityContext != 0:
+		return '.'
+	}
+	return ' '
+}
+
+// StringWithExtras is String with the 11th character filled in from x, like
+// "-rw-r--r--+".
+func (m Mode) StringWithExtras(x Extras) string {
+	buf := []byte(m.String())
+	buf[10] = x.Letter()
+	return string(buf)
+}
+
+// FileModeStringWithExtras is FileModeString with the 11th character filled
+// in from x.
+func FileModeStringWithExtras(m fs.FileMode, x Extras) string {
+	buf := []byte(FileModeString(m))
+	buf[10] = x.Letter()
+	return string(buf)
+}
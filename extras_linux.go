@@ -0,0 +1,60 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// PathExtras looks up the Extras of a file without following a trailing
+// symbolic link, as ls -l does.  An ACL counts when the access ACL holds
+// more than the permission bits or a default ACL exists.
+func PathExtras(name string) (Extras, error) {
+	var x Extras
+	b, err := lgetxattr(name, XattrACLAccess)
+	switch err {
+	case nil:
+		if entries, err := UnmarshalACLXattr(b); err == nil && (&ACL{Access: entries}).IsExtended() {
+			x |= ExtraACL
+		}
+	case syscall.ENODATA, syscall.ENOTSUP:
+	default:
+		return 0, &fs.PathError{Op: "lgetxattr", Path: name, Err: err}
+	}
+	if x == 0 {
+		if _, err = lgetxattr(name, XattrACLDefault); err == nil {
+			x |= ExtraACL
+		}
+	}
+	if _, err = lgetxattr(name, XattrSELinux); err == nil {
+		x |= ExtraSecurityContext
+	}
+	return x, nil
+}
+
+// LongString returns the mode string of a file as ls -l prints it, with the
+// 11th character showing an ACL or security context.
+func LongString(name string) (string, error) {
+	st, err := Lstat(name)
+	if err != nil {
+		return "", err
+	}
+	x, err := PathExtras(name)
+	if err != nil {
+		return "", err
+	}
+	return st.Mode.StringWithExtras(x), nil
+}
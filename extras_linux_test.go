@@ -0,0 +1,41 @@
+package unixmode_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func TestLongString(t *testing.T) {
+	f, err := os.CreateTemp("", "extras")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	if err := unixmode.Chmod(f.Name(), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	// A security context may already show as '.', but never an ACL
+	s, err := unixmode.LongString(f.Name())
+	if err != nil || s[:10] != "-rw-r-----" || s[10] == '+' {
+		t.Fatalf("LongString before SetACL = %q, %v", s, err)
+	}
+
+	acl := unixmode.NewACL(0640)
+	if err := acl.Modify("u:1234:r"); err != nil {
+		t.Fatal(err)
+	}
+	if err := unixmode.SetACL(f.Name(), acl); errors.Is(err, syscall.ENOTSUP) || errors.Is(err, syscall.EOPNOTSUPP) {
+		t.Skip("no ACL support in", os.TempDir())
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	if s, err = unixmode.LongString(f.Name()); s != "-rw-r-----+" || err != nil {
+		t.Errorf("LongString after SetACL = %q, %v, want %q", s, err, "-rw-r-----+")
+	}
+}
@@ -0,0 +1,24 @@
+package unixmode_test
+
+import (
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleParseExtras() {
+	for _, in := range []string{"-rw-r--r--+", "drwxr-xr-x.", "-rw-r--r-- ", "-rw-r--r--", "-rw-r--r--@"} {
+		m, x, err := unixmode.ParseExtras(in)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Printf("%q\n", m.StringWithExtras(x))
+	}
+	// Output:
+	// "-rw-r--r--+"
+	// "drwxr-xr-x."
+	// "-rw-r--r-- "
+	// "-rw-r--r-- "
+	// Invalid '@' at position 10
+}
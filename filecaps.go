@@ -0,0 +1,273 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var capNames = [...]string{
+	"chown", "dac_override", "dac_read_search", "fowner", "fsetid", "kill",
+	"setgid", "setuid", "setpcap", "linux_immutable", "net_bind_service",
+	"net_broadcast", "net_admin", "net_raw", "ipc_lock", "ipc_owner",
+	"sys_module", "sys_rawio", "sys_chroot", "sys_ptrace", "sys_pacct",
+	"sys_admin", "sys_boot", "sys_nice", "sys_resource", "sys_time",
+	"sys_tty_config", "mknod", "lease", "audit_write", "audit_control",
+	"setfcap", "mac_override", "mac_admin", "syslog", "wake_alarm",
+	"block_suspend", "audit_read", "perfmon", "bpf", "checkpoint_restore",
+}
+
+// String returns the name libcap uses, like "cap_net_raw".  Unknown numbers
+// are printed as decimal.
+func (c Capability) String() string {
+	if int(c) < len(capNames) {
+		return "cap_" + capNames[c]
+	}
+	return strconv.Itoa(int(c))
+}
+
+// ParseCapability accepts a capability name with or without the "cap_"
+// prefix, in any case, or its number.
+func ParseCapability(name string) (Capability, error) {
+	n := strings.TrimPrefix(strings.ToLower(name), "cap_")
+	for i, cn := range capNames {
+		if n == cn {
+			return Capability(i), nil
+		}
+	}
+	if v, err := strconv.ParseUint(name, 10, 8); err == nil && v < 64 {
+		return Capability(v), nil
+	}
+	return 0, fmt.Errorf("%w: unknown capability %q", ErrorFileCaps, name)
+}
+
+// AllCaps is the set of every capability known to this package.
+const AllCaps = CapSet(1)<<(CapLast+1) - 1
+
+// String lists the capabilities in the set, separated by commas.
+func (s CapSet) String() string {
+	var names []string
+	for c := Capability(0); c < 64; c++ {
+		if s.Has(c) {
+			names = append(names, c.String())
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// FileCaps are the capabilities of an executable file, stored in the
+// security.capability extended attribute.  Unlike a process, a file has a
+// single effective bit: when set, every permitted and inheritable capability
+// is raised in the effective set on execve(2).
+type FileCaps struct {
+	Permitted   CapSet
+	Inheritable CapSet
+	Effective   bool
+
+	// RootID is the user namespace root the capabilities are valid for,
+	// only stored by the revision 3 format.
+	RootID uint32
+}
+
+// XattrCapability is the extended attribute holding file capabilities.
+const XattrCapability = "security.capability"
+
+var ErrorFileCaps = errors.New("Invalid File Capabilities")
+
+// The magic_etc field of struct vfs_cap_data.
+const (
+	vfsCapRevisionMask = 0xFF000000
+	vfsCapRevision1    = 0x01000000
+	vfsCapRevision2    = 0x02000000
+	vfsCapRevision3    = 0x03000000
+	vfsCapFlagsEff     = 0x000001
+)
+
+// IsEmpty reports whether no capability is granted.
+func (c *FileCaps) IsEmpty() bool {
+	return c.Permitted == 0 && c.Inheritable == 0
+}
+
+// MarshalBinary encodes the capabilities into the security.capability format,
+// revision 2, or revision 3 when RootID is set.
+func (c *FileCaps) MarshalBinary() ([]byte, error) {
+	size, magic := 20, uint32(vfsCapRevision2)
+	if c.RootID != 0 {
+		size, magic = 24, vfsCapRevision3
+	}
+	if c.Effective {
+		magic |= vfsCapFlagsEff
+	}
+	b := make([]byte, size)
+	binary.LittleEndian.PutUint32(b[0:], magic)
+	binary.LittleEndian.PutUint32(b[4:], uint32(c.Permitted))
+	binary.LittleEndian.PutUint32(b[8:], uint32(c.Inheritable))
+	binary.LittleEndian.PutUint32(b[12:], uint32(c.Permitted>>32))
+	binary.LittleEndian.PutUint32(b[16:], uint32(c.Inheritable>>32))
+	if c.RootID != 0 {
+		binary.LittleEndian.PutUint32(b[20:], c.RootID)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes the security.capability format, revisions 1 to 3.
+func (c *FileCaps) UnmarshalBinary(b []byte) error {
+	if len(b) < 4 {
+		return fmt.Errorf("%w: %d bytes is too short", ErrorFileCaps, len(b))
+	}
+	magic := binary.LittleEndian.Uint32(b)
+	want := 0
+	switch magic & vfsCapRevisionMask {
+	case vfsCapRevision1:
+		want = 12
+	case vfsCapRevision2:
+		want = 20
+	case vfsCapRevision3:
+		want = 24
+	default:
+		return fmt.Errorf("%w: unknown revision %#x", ErrorFileCaps, magic&vfsCapRevisionMask)
+	}
+	if len(b) != want {
+		return fmt.Errorf("%w: revision %d needs %d bytes, got %d", ErrorFileCaps, magic>>24, want, len(b))
+	}
+	*c = FileCaps{
+		Effective:   magic&vfsCapFlagsEff != 0,
+		Permitted:   CapSet(binary.LittleEndian.Uint32(b[4:])),
+		Inheritable: CapSet(binary.LittleEndian.Uint32(b[8:])),
+	}
+	if want >= 20 {
+		c.Permitted |= CapSet(binary.LittleEndian.Uint32(b[12:])) << 32
+		c.Inheritable |= CapSet(binary.LittleEndian.Uint32(b[16:])) << 32
+	}
+	if want == 24 {
+		c.RootID = binary.LittleEndian.Uint32(b[20:])
+	}
+	return nil
+}
+
+// String returns the capabilities in the text format of getcap(8), like
+// "cap_net_bind_service,cap_net_raw=ep".  Capabilities sharing the same
+// flags are grouped, and "=ep" alone means all capabilities.
+func (c *FileCaps) String() string {
+	var order []string
+	groups := map[string]CapSet{}
+	for n := Capability(0); n < 64; n++ {
+		var flags []byte
+		if c.Effective && (c.Permitted | c.Inheritable).Has(n) {
+			flags = append(flags, 'e')
+		}
+		if c.Inheritable.Has(n) {
+			flags = append(flags, 'i')
+		}
+		if c.Permitted.Has(n) {
+			flags = append(flags, 'p')
+		}
+		if len(flags) == 0 {
+			continue
+		}
+		if _, ok := groups[string(flags)]; !ok {
+			order = append(order, string(flags))
+		}
+		groups[string(flags)] |= 1 << n
+	}
+	var out []string
+	for _, flags := range order {
+		if groups[flags] == AllCaps {
+			out = append(out, "="+flags)
+		} else {
+			out = append(out, groups[flags].String()+"="+flags)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// ParseFileCaps reads the text format used by setcap(8) and printed by
+// getcap(8).  The clauses are separated by spaces, each is a comma separated
+// list of capabilities, or none or "all" for every one, followed by one or
+// more operators '=', '+', or '-' with the flags 'e', 'i', and 'p'.  As a
+// file has a single effective bit, 'e' must cover either none or all of the
+// permitted and inheritable capabilities.
+func ParseFileCaps(text string) (*FileCaps, error) {
+	c := &FileCaps{}
+	var eff CapSet
+	for _, clause := range strings.Fields(text) {
+		i := strings.IndexAny(clause, "=+-")
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %q has no operator", ErrorFileCaps, clause)
+		}
+		var caps CapSet
+		if names := clause[:i]; names == "" || names == "all" {
+			caps = AllCaps
+		} else {
+			for _, name := range strings.Split(names, ",") {
+				n, err := ParseCapability(name)
+				if err != nil {
+					return nil, err
+				}
+				caps |= 1 << n
+			}
+		}
+		for rest := clause[i:]; rest != ""; {
+			op := rest[0]
+			j := strings.IndexAny(rest[1:], "=+-") + 1
+			if j == 0 {
+				j = len(rest)
+			}
+			flags := rest[1:j]
+			rest = rest[j:]
+			if op == '=' {
+				eff &^= caps
+				c.Inheritable &^= caps
+				c.Permitted &^= caps
+			}
+			for k := 0; k < len(flags); k++ {
+				var set *CapSet
+				switch flags[k] {
+				case 'e':
+					set = &eff
+				case 'i':
+					set = &c.Inheritable
+				case 'p':
+					set = &c.Permitted
+				default:
+					return nil, fmt.Errorf("%w: unknown flag %q in %q", ErrorFileCaps, flags[k], clause)
+				}
+				if op == '-' {
+					*set &^= caps
+				} else {
+					*set |= caps
+				}
+			}
+		}
+	}
+	if eff != 0 && eff != c.Permitted|c.Inheritable {
+		return nil, fmt.Errorf("%w: effective must be set for all or none of the permitted and inheritable capabilities", ErrorFileCaps)
+	}
+	c.Effective = eff != 0
+	return c, nil
+}
+
+// StringWithCaps returns the mode string followed by the file capabilities,
+// like "-rwxr-xr-x  cap_net_raw=ep", for privilege reports.
+func (m Mode) StringWithCaps(c *FileCaps) string {
+	if c == nil || c.IsEmpty() {
+		return m.String()
+	}
+	return m.String() + " " + c.String()
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// GetFileCaps reads the capabilities of a file.  A file without the
+// security.capability attribute returns empty FileCaps.
+func GetFileCaps(name string) (*FileCaps, error) {
+	b, err := getxattr(name, XattrCapability)
+	switch err {
+	case nil:
+	case syscall.ENODATA, syscall.ENOTSUP:
+		return &FileCaps{}, nil
+	default:
+		return nil, &fs.PathError{Op: "getcap", Path: name, Err: err}
+	}
+	c := &FileCaps{}
+	if err = c.UnmarshalBinary(b); err != nil {
+		return nil, &fs.PathError{Op: "getcap", Path: name, Err: err}
+	}
+	return c, nil
+}
+
+// SetFileCaps writes the capabilities of a file, empty FileCaps remove the
+// attribute.  This needs CAP_SETFCAP.  Note that the kernel clears the
+// attribute whenever the file is written to or its owner changes.
+func SetFileCaps(name string, c *FileCaps) error {
+	var err error
+	if c == nil || c.IsEmpty() {
+		if err = syscall.Removexattr(name, XattrCapability); err == syscall.ENODATA {
+			err = nil
+		}
+	} else {
+		b, _ := c.MarshalBinary()
+		err = syscall.Setxattr(name, XattrCapability, b, 0)
+	}
+	if err != nil {
+		return &fs.PathError{Op: "setcap", Path: name, Err: err}
+	}
+	return nil
+}
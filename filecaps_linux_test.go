@@ -0,0 +1,40 @@
+package unixmode_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func TestSetFileCaps(t *testing.T) {
+	f, err := os.CreateTemp("", "caps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	c, err := unixmode.ParseFileCaps("cap_net_bind_service=ep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unixmode.SetFileCaps(f.Name(), c); errors.Is(err, syscall.EPERM) {
+		t.Skip("setting file capabilities needs CAP_SETFCAP")
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	got, err := unixmode.GetFileCaps(f.Name())
+	if err != nil || got.String() != "cap_net_bind_service=ep" {
+		t.Errorf("GetFileCaps = %v, %v, want cap_net_bind_service=ep", got, err)
+	}
+
+	if err := unixmode.SetFileCaps(f.Name(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = unixmode.GetFileCaps(f.Name()); err != nil || !got.IsEmpty() {
+		t.Errorf("GetFileCaps after removal = %v, %v, want empty", got, err)
+	}
+}
@@ -0,0 +1,40 @@
+package unixmode_test
+
+import (
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleParseFileCaps() {
+	for _, text := range []string{"cap_net_bind_service,cap_net_raw=ep", "cap_net_raw+p cap_sys_admin=i", "=ep", "cap_kill,cap_chown=ip cap_kill-i", "cap_kill=ep cap_chown=p"} {
+		c, err := unixmode.ParseFileCaps(text)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(c)
+	}
+	// Output:
+	// cap_net_bind_service,cap_net_raw=ep
+	// cap_net_raw=p cap_sys_admin=i
+	// =ep
+	// cap_chown=ip cap_kill=p
+	// Invalid File Capabilities: effective must be set for all or none of the permitted and inheritable capabilities
+}
+
+func ExampleFileCaps_MarshalBinary() {
+	c, _ := unixmode.ParseFileCaps("cap_net_raw,cap_bpf=ep")
+	c.RootID = 1000
+	b, _ := c.MarshalBinary()
+	fmt.Printf("% x\n", b)
+
+	var d unixmode.FileCaps
+	err := d.UnmarshalBinary(b)
+	fmt.Println(d.String(), d.RootID, err)
+	fmt.Println(unixmode.Mode(0100755).StringWithCaps(&d))
+	// Output:
+	// 01 00 00 03 00 20 00 00 00 00 00 00 80 00 00 00 00 00 00 00 e8 03 00 00
+	// cap_net_raw,cap_bpf=ep 1000 <nil>
+	// -rwxr-xr-x  cap_net_raw,cap_bpf=ep
+}
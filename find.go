@@ -0,0 +1,146 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A Predicate is one test of a find(1) expression, evaluated against the
+// stat data of a file.
+type Predicate func(st *FileStat) bool
+
+// ErrorFind is wrapped by the errors from ParsePerm and ParseFind.
+var ErrorFind = errors.New("Invalid Find Expression")
+
+// PermExact matches files whose permission, setuid, setgid, and sticky bits
+// are exactly m, as find -perm MODE.
+func PermExact(m Mode) Predicate {
+	return func(st *FileStat) bool { return st.Mode&07777 == m&07777 }
+}
+
+// PermAll matches files with all of the bits of m set, as find -perm -MODE.
+func PermAll(m Mode) Predicate {
+	return func(st *FileStat) bool { return st.Mode&m&07777 == m&07777 }
+}
+
+// PermAny matches files with any of the bits of m set, as find -perm /MODE.
+// As with GNU find, a zero m matches every file.
+func PermAny(m Mode) Predicate {
+	return func(st *FileStat) bool { return m&07777 == 0 || st.Mode&m&07777 != 0 }
+}
+
+// ParsePerm compiles the argument of find -perm.  A leading '-' selects
+// PermAll and a leading '/' PermAny.  The mode is octal, like "4000", or
+// symbolic, like "g+w,o+w", in which case it is applied to a mode of 0.
+func ParsePerm(arg string) (Predicate, error) {
+	test, s := PermExact, arg
+	switch {
+	case strings.HasPrefix(s, "-"):
+		test, s = PermAll, s[1:]
+	case strings.HasPrefix(s, "/"):
+		test, s = PermAny, s[1:]
+	}
+	m, err := ParseOctalPerm(s)
+	if err != nil && len(s) > 0 && (s[0] < '0' || s[0] > '7') {
+		m, err = Mode(0).Apply(s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: -perm %q: %v", ErrorFind, arg, err)
+	}
+	return test(m), nil
+}
+
+// HasType matches files of any of the given types, such as ModeDir.
+func HasType(types ...Mode) Predicate {
+	return func(st *FileStat) bool {
+		for _, t := range types {
+			if st.Mode.Type() == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// findTypes maps the find -type letters to the file types.
+var findTypes = map[byte]Mode{
+	'b': ModeDevice,
+	'c': ModeCharDevice,
+	'd': ModeDir,
+	'p': ModeNamedPipe,
+	'f': ModeRegular,
+	'l': ModeSymlink,
+	's': ModeSocket,
+	'D': ModeDoor,
+}
+
+// ParseType compiles the argument of find -type, a type letter or a comma
+// separated list of them, like "f,l".
+func ParseType(arg string) (Predicate, error) {
+	var types []Mode
+	for _, s := range strings.Split(arg, ",") {
+		t, ok := Mode(0), false
+		if len(s) == 1 {
+			t, ok = findTypes[s[0]]
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: -type %q: unknown type %q", ErrorFind, arg, s)
+		}
+		types = append(types, t)
+	}
+	return HasType(types...), nil
+}
+
+// HasUser matches files owned by uid, as find -user.
+func HasUser(uid uint32) Predicate {
+	return func(st *FileStat) bool { return st.Uid == uid }
+}
+
+// HasGroup matches files belonging to gid, as find -group.
+func HasGroup(gid uint32) Predicate {
+	return func(st *FileStat) bool { return st.Gid == gid }
+}
+
+// HasAccess matches files which the credential c may access as wanted, as
+// find -readable, -writable, and -executable.  The check is Mode.Allows, so
+// ACLs are not consulted.
+func HasAccess(c *Credential, want Access) Predicate {
+	return func(st *FileStat) bool { return st.Mode.Allows(c, st.Uid, st.Gid, want) }
+}
+
+// NewerThan matches files modified after t, as find -newer.
+func NewerThan(t time.Time) Predicate {
+	return func(st *FileStat) bool { return st.Mtime.After(t) }
+}
+
+// Not matches the files p does not, as find ! EXPR.
+func (p Predicate) Not() Predicate {
+	return func(st *FileStat) bool { return !p(st) }
+}
+
+// And matches the files both p and q match, as find EXPR -a EXPR.  The
+// second test is skipped when the first fails.
+func (p Predicate) And(q Predicate) Predicate {
+	return func(st *FileStat) bool { return p(st) && q(st) }
+}
+
+// Or matches the files either p or q match, as find EXPR -o EXPR.
+func (p Predicate) Or(q Predicate) Predicate {
+	return func(st *FileStat) bool { return p(st) || q(st) }
+}
@@ -0,0 +1,221 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"fmt"
+	"io/fs"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+// ParseFind compiles a find(1) expression given as separate arguments, like
+// the command line of find after the starting points:
+//
+//	unixmode.ParseFind("-type", "f", "(", "-perm", "-o+w", "-o", "!", "-user", "root", ")")
+//
+// The tests are -perm, -type, -user, -group, -readable, -writable,
+// -executable, and -newer.  They combine with "(" and ")", "!" or -not,
+// -a or -and, which is implied between two tests, and -o or -or, in order of
+// decreasing precedence.  No arguments match every file.
+//
+// User and group names are looked up when parsing, -newer reads the
+// modification time of its file, and the access tests use the
+// AccessCredential of the process.
+func ParseFind(args ...string) (Predicate, error) {
+	if len(args) == 0 {
+		return func(*FileStat) bool { return true }, nil
+	}
+	p := &findParser{args: args}
+	pred, err := p.or()
+	if err == nil && p.i < len(args) {
+		err = p.fail("unexpected %q", args[p.i])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pred, nil
+}
+
+type findParser struct {
+	args []string
+	i    int
+	cred *Credential
+}
+
+func (p *findParser) fail(format string, a ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrorFind, fmt.Sprintf(format, a...))
+}
+
+func (p *findParser) peek() string {
+	if p.i < len(p.args) {
+		return p.args[p.i]
+	}
+	return ""
+}
+
+func (p *findParser) or() (Predicate, error) {
+	pred, err := p.and()
+	for err == nil && (p.peek() == "-o" || p.peek() == "-or") {
+		p.i++
+		var q Predicate
+		if q, err = p.and(); err == nil {
+			pred = pred.Or(q)
+		}
+	}
+	return pred, err
+}
+
+func (p *findParser) and() (Predicate, error) {
+	pred, err := p.not()
+	for err == nil {
+		switch p.peek() {
+		case "-a", "-and":
+			p.i++
+		case "", ")", "-o", "-or":
+			return pred, nil
+		}
+		var q Predicate
+		if q, err = p.not(); err == nil {
+			pred = pred.And(q)
+		}
+	}
+	return pred, err
+}
+
+func (p *findParser) not() (Predicate, error) {
+	if tok := p.peek(); tok == "!" || tok == "-not" {
+		p.i++
+		pred, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return pred.Not(), nil
+	}
+	return p.primary()
+}
+
+func (p *findParser) primary() (Predicate, error) {
+	if p.i == len(p.args) {
+		return nil, p.fail("expected an expression at the end")
+	}
+	tok := p.args[p.i]
+	p.i++
+	switch tok {
+	case "(":
+		pred, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, p.fail("missing \")\"")
+		}
+		p.i++
+		return pred, nil
+	case "-readable", "-writable", "-executable":
+		if p.cred == nil {
+			c, err := AccessCredential()
+			if err != nil {
+				return nil, p.fail("%s: %v", tok, err)
+			}
+			p.cred = c
+		}
+		want := map[string]Access{"-readable": AccessRead, "-writable": AccessWrite, "-executable": AccessExec}[tok]
+		return HasAccess(p.cred, want), nil
+	case "-perm", "-type", "-user", "-group", "-newer":
+	default:
+		return nil, p.fail("unknown predicate %q", tok)
+	}
+
+	if p.i == len(p.args) {
+		return nil, p.fail("missing argument to %s", tok)
+	}
+	arg := p.args[p.i]
+	p.i++
+	switch tok {
+	case "-perm":
+		return ParsePerm(arg)
+	case "-type":
+		return ParseType(arg)
+	case "-user":
+		if u, err := user.Lookup(arg); err == nil {
+			arg = u.Uid
+		}
+		uid, err := strconv.ParseUint(arg, 10, 32)
+		if err != nil {
+			return nil, p.fail("-user %q is not the name of a known user", p.args[p.i-1])
+		}
+		return HasUser(uint32(uid)), nil
+	case "-group":
+		if g, err := user.LookupGroup(arg); err == nil {
+			arg = g.Gid
+		}
+		gid, err := strconv.ParseUint(arg, 10, 32)
+		if err != nil {
+			return nil, p.fail("-group %q is not the name of an existing group", p.args[p.i-1])
+		}
+		return HasGroup(uint32(gid)), nil
+	}
+	// -newer, the reference is not followed when it is a symbolic link
+	st, err := Lstat(arg)
+	if err != nil {
+		return nil, p.fail("-newer %q: %v", arg, err)
+	}
+	return NewerThan(st.Mtime), nil
+}
+
+// Find walks the tree rooted at root in lexical order, without following
+// symbolic links, and returns the paths matching p, the root included.
+// Like find(1) it reports a failure and carries on: an entry which cannot be
+// stat'ed is skipped, and a directory which cannot be read is still tested
+// but not entered.  The matches are then returned along with a *TreeError
+// listing each failure.
+func Find(root string, p Predicate) ([]string, error) {
+	var found []string
+	var errs []*fs.PathError
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// An unreadable directory was already tested on its first visit
+			errs = append(errs, findPathError(path, err))
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			errs = append(errs, findPathError(path, err))
+			return nil
+		}
+		st, ok := FileInfoStat(fi)
+		if !ok {
+			errs = append(errs, &fs.PathError{Op: "stat", Path: path, Err: fs.ErrInvalid})
+			return nil
+		}
+		if p(st) {
+			found = append(found, path)
+		}
+		return nil
+	})
+	if len(errs) > 0 {
+		return found, &TreeError{Errors: errs}
+	}
+	return found, nil
+}
+
+func findPathError(path string, err error) *fs.PathError {
+	if pe, ok := err.(*fs.PathError); ok {
+		return pe
+	}
+	return &fs.PathError{Op: "lstat", Path: path, Err: err}
+}
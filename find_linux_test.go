@@ -0,0 +1,98 @@
+package unixmode_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleFind() {
+	root, _ := os.MkdirTemp("", "find")
+	defer os.RemoveAll(root)
+	os.Mkdir(filepath.Join(root, "bin"), 0755)
+	os.WriteFile(filepath.Join(root, "bin", "tool"), nil, 0755)
+	os.WriteFile(filepath.Join(root, "notes"), nil, 0644)
+	os.WriteFile(filepath.Join(root, "shared"), nil, 0644)
+	os.Chmod(filepath.Join(root, "shared"), 0666)
+	os.Symlink("bin/tool", filepath.Join(root, "tool"))
+
+	for _, args := range [][]string{
+		{"-type", "f", "-perm", "/111"},
+		{"-type", "f", "-a", "(", "-perm", "-o+w", "-o", "-name", ")"},
+		{"!", "-type", "d", "-perm", "-o+w", "-o", "-type", "l"},
+	} {
+		p, err := unixmode.ParseFind(args...)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		found, err := unixmode.Find(root, p)
+		for i := range found {
+			found[i], _ = filepath.Rel(root, found[i])
+		}
+		fmt.Println(strings.Join(args, " "), "=>", found, err)
+	}
+	// Output:
+	// -type f -perm /111 => [bin/tool] <nil>
+	// Invalid Find Expression: unknown predicate "-name"
+	// ! -type d -perm -o+w -o -type l => [shared tool] <nil>
+}
+
+func ExampleParseFind_newer() {
+	_, err := unixmode.ParseFind("-type", "f", "-newer", "/nonexistent")
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, unixmode.ErrorFind))
+	// Output:
+	// Invalid Find Expression: -newer "/nonexistent": lstat /nonexistent: no such file or directory
+	// true
+}
+
+func TestFindUser(t *testing.T) {
+	root, err := os.MkdirTemp("", "find")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	os.Mkdir(filepath.Join(root, "bin"), 0755)
+	os.WriteFile(filepath.Join(root, "bin", "tool"), nil, 0755)
+	os.Symlink("bin/tool", filepath.Join(root, "tool"))
+
+	// Everything is owned by the user running the test
+	uid := os.Getuid()
+	var rootOwned []string
+	if uid == 0 {
+		rootOwned = []string{".", "bin", "tool"}
+	}
+	for _, tc := range []struct {
+		user string
+		want []string
+	}{
+		{strconv.Itoa(uid), []string{".", "bin", "tool"}},
+		{"0", rootOwned},
+		{"4000000000", nil},
+	} {
+		p, err := unixmode.ParseFind("-type", "l,d", "-user", tc.user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err := unixmode.Find(root, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, f := range found {
+			rel, _ := filepath.Rel(root, f)
+			got = append(got, rel)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("-type l,d -user %s found %q, want %q", tc.user, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package unixmode_test
+
+import (
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleParsePerm() {
+	files := []unixmode.FileStat{
+		{Mode: unixmode.ModeRegular | 0644},
+		{Mode: unixmode.ModeRegular | 0664},
+		{Mode: unixmode.ModeRegular | 04755},
+		{Mode: unixmode.ModeDir | 01777},
+	}
+	for _, arg := range []string{"644", "-g+w", "/u+s,o+t", "-0755", "/000", "-perm"} {
+		p, err := unixmode.ParsePerm(arg)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Printf("%-9s", arg)
+		for _, st := range files {
+			fmt.Printf(" %v", p(&st))
+		}
+		fmt.Println()
+	}
+	// Output:
+	// 644       true false false false
+	// -g+w      false true false true
+	// /u+s,o+t  false false true true
+	// -0755     false false true true
+	// /000      true true true true
+	// Invalid Find Expression: -perm "-perm": Invalid Symbolic Mode "perm" at offset 0: unexpected 'p', expected one of "ugoa+-="
+}
+
+func ExamplePredicate() {
+	// find -type f ( -perm -o+w -o ! -readable ), as seen by uid 1000
+	cred := &unixmode.Credential{Uid: 1000, Gid: 1000}
+	typ, _ := unixmode.ParseType("f")
+	worldWritable := unixmode.PermAll(unixmode.ModeWriteOther)
+	readable := unixmode.HasAccess(cred, unixmode.AccessRead)
+	p := typ.And(worldWritable.Or(readable.Not()))
+
+	for _, st := range []unixmode.FileStat{
+		{Mode: unixmode.ModeRegular | 0644, Uid: 1000},
+		{Mode: unixmode.ModeRegular | 0666, Uid: 1000},
+		{Mode: unixmode.ModeRegular | 0600, Uid: 0},
+		{Mode: unixmode.ModeDir | 0777, Uid: 0},
+	} {
+		fmt.Println(st.Mode.Octal(unixmode.OctalFull), st.Uid, p(&st))
+	}
+	// Output:
+	// 100644 1000 false
+	// 100666 1000 true
+	// 100600 0 true
+	// 040777 0 false
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// A GitMode is the mode of a git tree entry, as shown by git ls-tree.  Git
+// only records the five modes below.
+type GitMode uint32
+
+const (
+	GitTree       GitMode = 0040000 /* directory */
+	GitFile       GitMode = 0100644 /* regular file */
+	GitExecutable GitMode = 0100755 /* regular file with execute permission */
+	GitSymlink    GitMode = 0120000 /* symbolic link, the blob holds the target */
+	GitGitlink    GitMode = 0160000 /* submodule commit */
+)
+
+// ModeGitlink is the type of a submodule entry.  Git uses S_IFLNK|S_IFDIR,
+// the same value as ModeWhiteout, so TypeLetter shows it as 'w'.
+const ModeGitlink = ModeWhiteout
+
+var ErrorGitMode = errors.New("Invalid Git Mode")
+
+// String returns the mode as git ls-tree prints it, like "100644".
+func (g GitMode) String() string {
+	return fmt.Sprintf("%06o", uint32(g))
+}
+
+// ObjectType returns the type of object the entry points at, "tree",
+// "blob", or "commit".
+func (g GitMode) ObjectType() string {
+	switch g {
+	case GitTree:
+		return "tree"
+	case GitGitlink:
+		return "commit"
+	}
+	return "blob"
+}
+
+// ParseGitMode reads an octal git mode, like "100755".  Older git versions
+// wrote regular files with other permissions, like "100664", so the mode is
+// normalized the same way git does: regular files become GitExecutable when
+// the owner execute bit is set and GitFile otherwise.
+func ParseGitMode(in string) (GitMode, error) {
+	v, err := strconv.ParseUint(in, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrorGitMode, in)
+	}
+	g, err := Mode(v).GitMode()
+	if err != nil || v > 0177777 || (g != GitFile && g != GitExecutable && GitMode(v) != g) {
+		return 0, fmt.Errorf("%w: %q", ErrorGitMode, in)
+	}
+	return g, nil
+}
+
+// GitMode returns the mode git records for m.  A regular file with the
+// owner execute bit becomes GitExecutable and any other regular file
+// GitFile, the remaining permission bits are dropped.  Types git cannot
+// record, such as devices, result in ErrorGitMode.
+func (m Mode) GitMode() (GitMode, error) {
+	switch m.Type() {
+	case ModeRegular:
+		if m&ModeExecUser != 0 {
+			return GitExecutable, nil
+		}
+		return GitFile, nil
+	case ModeDir:
+		return GitTree, nil
+	case ModeSymlink:
+		return GitSymlink, nil
+	case ModeGitlink:
+		return GitGitlink, nil
+	}
+	return 0, fmt.Errorf("%w: %s has no git equivalent", ErrorGitMode, m.Octal(OctalFull))
+}
+
+// FromGitMode returns the Mode a checkout creates for a tree entry, before
+// the umask is applied: 0644 or 0755 for files, 0755 for trees, and 0777 for
+// symbolic links.  A gitlink becomes ModeGitlink without permissions.
+func FromGitMode(g GitMode) (Mode, error) {
+	switch g {
+	case GitFile:
+		return ModeRegular | 0644, nil
+	case GitExecutable:
+		return ModeRegular | 0755, nil
+	case GitTree:
+		return ModeDir | 0755, nil
+	case GitSymlink:
+		return ModeSymlink | 0777, nil
+	case GitGitlink:
+		return ModeGitlink, nil
+	}
+	return 0, fmt.Errorf("%w: %s", ErrorGitMode, g)
+}
+
+// GitRestoreExec returns m with execute permission added wherever read
+// permission is set when g is GitExecutable, as git does on checkout.  Any
+// other g, or an m which is not a regular file, returns m unchanged.  With
+// core.fileMode=false git neither records nor checks the execute bit, so
+// this puts back what the tree says after a copy or a file system lost it.
+func (m Mode) GitRestoreExec(g GitMode) Mode {
+	if g != GitExecutable || m.Type() != ModeRegular {
+		return m
+	}
+	return m | m&(ModeReadUser|ModeReadGroup|ModeReadOther)>>2
+}
+
+// GitRestoreExecPath applies GitRestoreExec to the file at name, leaving
+// symbolic links and files which already match alone.
+func GitRestoreExecPath(name string, g GitMode) error {
+	fi, err := os.Lstat(name)
+	if err != nil {
+		return err
+	}
+	m := New(fi.Mode())
+	if to := m.GitRestoreExec(g); to != m {
+		return Chmod(name, to)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package unixmode_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleMode_GitMode() {
+	for _, m := range []unixmode.Mode{
+		unixmode.ModeRegular | 0664,
+		unixmode.ModeRegular | 0744,
+		unixmode.ModeRegular | 0654,
+		unixmode.ModeDir | 0700,
+		unixmode.ModeSymlink | 0777,
+		unixmode.ModeGitlink,
+		unixmode.ModeNamedPipe | 0644,
+	} {
+		g, err := m.GitMode()
+		fmt.Println(m.Octal(unixmode.OctalFull), g, g.ObjectType(), err)
+	}
+	// Output:
+	// 100664 100644 blob <nil>
+	// 100744 100755 blob <nil>
+	// 100654 100644 blob <nil>
+	// 040700 040000 tree <nil>
+	// 120777 120000 blob <nil>
+	// 160000 160000 commit <nil>
+	// 010644 000000 blob Invalid Git Mode: 010644 has no git equivalent
+}
+
+func ExampleFromGitMode() {
+	for _, s := range []string{"100644", "100755", "100664", "040000", "120000", "160000", "100755x", "040755"} {
+		g, err := unixmode.ParseGitMode(s)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		m, _ := unixmode.FromGitMode(g)
+		fmt.Println(s, g, m.Octal(unixmode.OctalFull))
+	}
+	// Output:
+	// 100644 100644 100644
+	// 100755 100755 100755
+	// 100664 100644 100644
+	// 040000 040000 040755
+	// 120000 120000 120777
+	// 160000 160000 160000
+	// Invalid Git Mode: "100755x"
+	// Invalid Git Mode: "040755"
+}
+
+func ExampleGitRestoreExecPath() {
+	f, _ := os.CreateTemp("", "script")
+	f.Close()
+	defer os.Remove(f.Name())
+	unixmode.Chmod(f.Name(), unixmode.ModeSetgid|0640)
+
+	// With core.fileMode=false the tree still says 100755
+	err := unixmode.GitRestoreExecPath(f.Name(), unixmode.GitExecutable)
+	fi, _ := os.Stat(f.Name())
+	fmt.Println(unixmode.New(fi.Mode()).PermString(), err)
+	// Output:
+	// rwxr-s--- <nil>
+}
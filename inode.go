@@ -0,0 +1,155 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// InodeFlags are the Linux inode attributes read and written by lsattr(1) and
+// chattr(1) through the FS_IOC_GETFLAGS and FS_IOC_SETFLAGS ioctls.
+type InodeFlags uint32
+
+const (
+	FlagSecureDelete InodeFlags = 0x00000001 /* s: secure deletion */
+	FlagUndelete     InodeFlags = 0x00000002 /* u: undelete */
+	FlagCompress     InodeFlags = 0x00000004 /* c: compress file */
+	FlagSync         InodeFlags = 0x00000008 /* S: synchronous updates */
+	FlagImmutable    InodeFlags = 0x00000010 /* i: immutable file */
+	FlagAppend       InodeFlags = 0x00000020 /* a: writes to file may only append */
+	FlagNodump       InodeFlags = 0x00000040 /* d: do not dump file */
+	FlagNoatime      InodeFlags = 0x00000080 /* A: do not update atime */
+	FlagNocompress   InodeFlags = 0x00000400 /* m: do not compress */
+	FlagEncrypted    InodeFlags = 0x00000800 /* E: encrypted file */
+	FlagIndex        InodeFlags = 0x00001000 /* I: hash-indexed directory */
+	FlagJournalData  InodeFlags = 0x00004000 /* j: journal file data */
+	FlagNotail       InodeFlags = 0x00008000 /* t: no tail-merging */
+	FlagDirsync      InodeFlags = 0x00010000 /* D: synchronous directory updates */
+	FlagTopdir       InodeFlags = 0x00020000 /* T: top of directory hierarchies */
+	FlagExtents      InodeFlags = 0x00080000 /* e: inode uses extents */
+	FlagVerity       InodeFlags = 0x00100000 /* V: verity protected inode */
+	FlagNocow        InodeFlags = 0x00800000 /* C: do not copy on write */
+	FlagDax          InodeFlags = 0x02000000 /* x: direct access */
+	FlagInlineData   InodeFlags = 0x10000000 /* N: inode has inline data */
+	FlagProjinherit  InodeFlags = 0x20000000 /* P: inherit the project ID */
+	FlagCasefold     InodeFlags = 0x40000000 /* F: case-insensitive directory */
+	FlagReadOnlyMask            = FlagEncrypted | FlagIndex | FlagExtents | FlagVerity | FlagInlineData
+)
+
+// The flags in the order lsattr prints them.
+var inodeFlagLetters = []struct {
+	flag InodeFlags
+	c    byte
+}{
+	{FlagSecureDelete, 's'}, {FlagUndelete, 'u'}, {FlagSync, 'S'},
+	{FlagDirsync, 'D'}, {FlagImmutable, 'i'}, {FlagAppend, 'a'},
+	{FlagNodump, 'd'}, {FlagNoatime, 'A'}, {FlagCompress, 'c'},
+	{FlagEncrypted, 'E'}, {FlagJournalData, 'j'}, {FlagIndex, 'I'},
+	{FlagNotail, 't'}, {FlagTopdir, 'T'}, {FlagExtents, 'e'},
+	{FlagNocow, 'C'}, {FlagDax, 'x'}, {FlagCasefold, 'F'},
+	{FlagInlineData, 'N'}, {FlagProjinherit, 'P'}, {FlagVerity, 'V'},
+	{FlagNocompress, 'm'},
+}
+
+var (
+	ErrorInodeFlags            = errors.New("Invalid Inode Flags")
+	ErrorInodeFlagsLength      = errors.New("Invalid Inode Flags Length")
+	ErrorInodeFlagsUnsupported = errors.New("Inode Flags Not Supported")
+)
+
+// String returns the flags in the fixed width format of lsattr(1), such as
+// "----i---------e-------".
+func (f InodeFlags) String() string {
+	buf := make([]byte, len(inodeFlagLetters))
+	for i, l := range inodeFlagLetters {
+		setIf(&buf[i], f&l.flag != 0, l.c, '-')
+	}
+	return string(buf)
+}
+
+// ParseInodeFlags reads the lsattr(1) format back into InodeFlags.  Each
+// position must hold its letter or '-'.
+func ParseInodeFlags(in string) (InodeFlags, error) {
+	if len(in) != len(inodeFlagLetters) {
+		return 0, &ParseError{Input: in, Offset: -1, Err: ErrorInodeFlagsLength}
+	}
+	var f InodeFlags
+	for i, l := range inodeFlagLetters {
+		switch in[i] {
+		case l.c:
+			f |= l.flag
+		case '-':
+		default:
+			return 0, &ParseError{Input: in, Offset: i, Byte: in[i], Expected: string([]byte{l.c, '-'}), Err: ErrorInodeFlags}
+		}
+	}
+	return f, nil
+}
+
+// A Chattr is a compiled chattr(1) expression, such as "+i -a =e".
+type Chattr struct {
+	ops []chattrOp
+}
+
+type chattrOp struct {
+	op    byte
+	flags InodeFlags
+}
+
+// ParseChattr compiles a chattr(1) style expression: whitespace separated
+// words of '+', '-', or '=' followed by attribute letters.
+func ParseChattr(expr string) (*Chattr, error) {
+	c := &Chattr{}
+	fields := strings.Fields(expr)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", ErrorInodeFlags)
+	}
+	for _, word := range fields {
+		op := chattrOp{op: word[0]}
+		if op.op != '+' && op.op != '-' && op.op != '=' {
+			return nil, fmt.Errorf("%w: %q must start with one of \"+-=\"", ErrorInodeFlags, word)
+		}
+	letters:
+		for i := 1; i < len(word); i++ {
+			for _, l := range inodeFlagLetters {
+				if word[i] == l.c {
+					op.flags |= l.flag
+					continue letters
+				}
+			}
+			return nil, fmt.Errorf("%w: unknown attribute %q in %q", ErrorInodeFlags, word[i], word)
+		}
+		c.ops = append(c.ops, op)
+	}
+	return c, nil
+}
+
+// Apply runs the expression against the flags.  An '=' word keeps the flags
+// in FlagReadOnlyMask, which are managed by the file system.
+func (c *Chattr) Apply(f InodeFlags) InodeFlags {
+	for _, op := range c.ops {
+		switch op.op {
+		case '+':
+			f |= op.flags
+		case '-':
+			f &^= op.flags
+		case '=':
+			f = f&FlagReadOnlyMask | op.flags
+		}
+	}
+	return f
+}
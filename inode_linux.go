@@ -0,0 +1,126 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// GetFlags reads the inode flags of a regular file or directory, without
+// following a trailing symbolic link.  Other file types are refused, as
+// opening a device or fifo to ask could block or have side effects.
+func GetFlags(name string) (InodeFlags, error) {
+	fd, err := openForFlags(name)
+	if err != nil {
+		return 0, &fs.PathError{Op: "getflags", Path: name, Err: err}
+	}
+	defer syscall.Close(fd)
+	v, err := ioctlGetFlags(fd)
+	if err != nil {
+		return 0, &fs.PathError{Op: "getflags", Path: name, Err: flagsError(err)}
+	}
+	return InodeFlags(v), nil
+}
+
+// SetFlags replaces the inode flags of a regular file or directory.  Setting
+// or clearing FlagImmutable or FlagAppend needs CAP_LINUX_IMMUTABLE.
+func SetFlags(name string, f InodeFlags) error {
+	fd, err := openForFlags(name)
+	if err != nil {
+		return &fs.PathError{Op: "setflags", Path: name, Err: err}
+	}
+	defer syscall.Close(fd)
+	if err = ioctlSetFlags(fd, uint32(f)); err != nil {
+		return &fs.PathError{Op: "setflags", Path: name, Err: flagsError(err)}
+	}
+	return nil
+}
+
+// ChattrPath applies a chattr(1) expression to the flags of a file and
+// returns the new flags.
+func ChattrPath(name string, c *Chattr) (InodeFlags, error) {
+	f, err := GetFlags(name)
+	if err != nil {
+		return 0, err
+	}
+	nf := c.Apply(f)
+	if nf == f {
+		return f, nil
+	}
+	return nf, SetFlags(name, nf)
+}
+
+// FileGetFlags reads the inode flags of an opened file.
+func FileGetFlags(f *os.File) (InodeFlags, error) {
+	var v uint32
+	err := fileControl(f, func(fd int) (err error) {
+		v, err = ioctlGetFlags(fd)
+		return
+	})
+	if err != nil {
+		return 0, &fs.PathError{Op: "getflags", Path: f.Name(), Err: flagsError(err)}
+	}
+	return InodeFlags(v), nil
+}
+
+// FileSetFlags replaces the inode flags of an opened file.
+func FileSetFlags(f *os.File, flags InodeFlags) error {
+	err := fileControl(f, func(fd int) error {
+		return ioctlSetFlags(fd, uint32(flags))
+	})
+	if err != nil {
+		return &fs.PathError{Op: "setflags", Path: f.Name(), Err: flagsError(err)}
+	}
+	return nil
+}
+
+// fileControl runs fn with the descriptor of f, without putting f into
+// blocking mode as f.Fd does.
+func fileControl(f *os.File, fn func(fd int) error) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ferr error
+	if err = rc.Control(func(fd uintptr) { ferr = fn(int(fd)) }); err != nil {
+		return err
+	}
+	return ferr
+}
+
+func openForFlags(name string) (int, error) {
+	var st syscall.Stat_t
+	if err := syscall.Lstat(name, &st); err != nil {
+		return -1, err
+	}
+	switch Mode(st.Mode) & ModeTypeMask {
+	case ModeRegular, ModeDir:
+	default:
+		return -1, fmt.Errorf("%w: only regular files and directories have flags", ErrorInodeFlagsUnsupported)
+	}
+	return syscall.Open(name, syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_NOFOLLOW|syscall.O_CLOEXEC, 0)
+}
+
+// flagsError explains the errors a file system without inode flags gives.
+func flagsError(err error) error {
+	switch err {
+	case syscall.ENOTTY, syscall.EOPNOTSUPP, syscall.ENOSYS:
+		return fmt.Errorf("%w: the file system does not implement FS_IOC_GETFLAGS/SETFLAGS (%v)", ErrorInodeFlagsUnsupported, err)
+	}
+	return err
+}
@@ -0,0 +1,26 @@
+package unixmode_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleGetFlags() {
+	_, err := unixmode.GetFlags("/dev/null")
+	fmt.Println(errors.Is(err, unixmode.ErrorInodeFlagsUnsupported))
+
+	f, _ := os.CreateTemp("", "flags")
+	defer os.Remove(f.Name())
+	defer f.Close()
+	flags, err := unixmode.FileGetFlags(f)
+	if errors.Is(err, unixmode.ErrorInodeFlagsUnsupported) {
+		flags, err = 0, nil // tmpfs on older kernels
+	}
+	fmt.Println(err, flags&unixmode.FlagImmutable == 0)
+	// Output:
+	// true
+	// <nil> true
+}
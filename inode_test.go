@@ -0,0 +1,38 @@
+package unixmode_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleInodeFlags_String() {
+	f := unixmode.FlagImmutable | unixmode.FlagExtents
+	fmt.Println(f)
+	p, err := unixmode.ParseInodeFlags("-----ad-------e-------")
+	fmt.Printf("%#x %v\n", uint32(p), err)
+	_, err = unixmode.ParseInodeFlags("-----x-d------e-------")
+	fmt.Println(err)
+	_, err = unixmode.ParseInodeFlags("----i----")
+	fmt.Println(err, errors.Is(err, unixmode.ErrorModeLength))
+	// Output:
+	// ----i---------e-------
+	// 0x80060 <nil>
+	// Invalid 'x' at position 5
+	// Invalid Inode Flags Length 9 for "----i----" false
+}
+
+func ExampleParseChattr() {
+	c, _ := unixmode.ParseChattr("+i -a +dA")
+	f := c.Apply(unixmode.FlagAppend | unixmode.FlagExtents)
+	fmt.Println(f)
+	c, _ = unixmode.ParseChattr("=S")
+	fmt.Println(c.Apply(f))
+	_, err := unixmode.ParseChattr("+q")
+	fmt.Println(err)
+	// Output:
+	// ----i-dA------e-------
+	// --S-----------e-------
+	// Invalid Inode Flags: unknown attribute 'q' in "+q"
+}
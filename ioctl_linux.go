@@ -0,0 +1,62 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// ioc builds an ioctl request number, as the _IOC macro does.  The direction
+// bits are laid out differently on mips and powerpc.
+func ioc(read, write bool, typ, nr byte, size uintptr) uintptr {
+	dirRead, dirWrite, dirShift := uintptr(2), uintptr(1), uintptr(30)
+	switch runtime.GOARCH {
+	case "mips", "mipsle", "mips64", "mips64le", "ppc64", "ppc64le":
+		dirRead, dirWrite, dirShift = 2, 4, 29
+	}
+	var dir uintptr
+	if read {
+		dir |= dirRead
+	}
+	if write {
+		dir |= dirWrite
+	}
+	return dir<<dirShift | size<<16 | uintptr(typ)<<8 | uintptr(nr)
+}
+
+// FS_IOC_GETFLAGS and FS_IOC_SETFLAGS are declared with a long argument but
+// the kernel reads and writes an int.
+var (
+	fsIocGetFlags = ioc(true, false, 'f', 1, unsafe.Sizeof(uintptr(0)))
+	fsIocSetFlags = ioc(false, true, 'f', 2, unsafe.Sizeof(uintptr(0)))
+)
+
+func ioctlGetFlags(fd int) (uint32, error) {
+	var v int32
+	if _, _, e := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), fsIocGetFlags, uintptr(unsafe.Pointer(&v))); e != 0 {
+		return 0, e
+	}
+	return uint32(v), nil
+}
+
+func ioctlSetFlags(fd int, flags uint32) error {
+	v := int32(flags)
+	if _, _, e := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), fsIocSetFlags, uintptr(unsafe.Pointer(&v))); e != 0 {
+		return e
+	}
+	return nil
+}
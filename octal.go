@@ -0,0 +1,107 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	ErrorOctal     = errors.New("Invalid Octal Mode")
+	ErrorModeRange = errors.New("Mode Out Of Range")
+	ErrorModeType  = errors.New("Invalid Mode Type")
+)
+
+// ParseOctal converts a numeric mode string into a Mode.  The accepted forms
+// are:
+//
+//	"755", "0755"   - permission bits, as given to chmod(1)
+//	"4755"          - permission bits with setuid, setgid, or sticky
+//	"0o755"         - Go style octal literal
+//	"100644"        - a full st_mode, including the ModeTypeMask bits
+//
+// The value must fit in 16 bits and any bits above 07777 must form one of the
+// known file types.
+func ParseOctal(in string) (Mode, error) {
+	s := in
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'o' || s[1] == 'O') {
+		s = s[2:]
+	}
+	if len(s) == 0 {
+		return 0, fmt.Errorf("%w: %q is empty", ErrorOctal, in)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '7' {
+			return 0, fmt.Errorf("%w: %q has non-octal digit %q", ErrorOctal, in, s[i])
+		}
+	}
+	v, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || v > 0177777 {
+		return 0, fmt.Errorf("%w: %q exceeds %o", ErrorModeRange, in, 0177777)
+	}
+	m := Mode(v)
+	if !m.validType() {
+		return 0, fmt.Errorf("%w: %q has type bits %06o", ErrorModeType, in, m&ModeTypeMask)
+	}
+	return m, nil
+}
+
+// ParseOctalPerm is like ParseOctal but only accepts permission bits, any
+// ModeTypeMask bits present result in ErrorModeRange.
+func ParseOctalPerm(in string) (Mode, error) {
+	m, err := ParseOctal(in)
+	if err == nil && m.Type() != 0 {
+		return 0, fmt.Errorf("%w: %q exceeds %o", ErrorModeRange, in, 07777)
+	}
+	return m, err
+}
+
+// validType reports if the type bits are unset or one of the known types.
+func (m Mode) validType() bool {
+	switch m & ModeTypeMask {
+	case 0, ModeNamedPipe, ModeCharDevice, ModeDir, ModeDevice,
+		ModeRegular, ModeSymlink, ModeSocket,
+		ModeContiguous, ModeDoor, ModeMultiplexedChar, ModeMultiplexedBlock,
+		ModeNetwork, ModePort, ModeWhiteout:
+		return true
+	}
+	return false
+}
+
+// OctalStyle selects the output format of Mode.Octal.
+type OctalStyle int
+
+const (
+	OctalChmod OctalStyle = iota // "0755", "4755" - four digit permissions
+	OctalStat                    // "755", "4755" - as stat -c %a
+	OctalGo                      // "0o755" - as a Go literal
+	OctalFull                    // "100644", "040755" - full st_mode, as git ls-tree
+)
+
+// Octal formats the Mode as an octal number in the given style.  Only
+// OctalFull includes the ModeTypeMask bits.
+func (m Mode) Octal(style OctalStyle) string {
+	switch style {
+	case OctalStat:
+		return strconv.FormatUint(uint64(m.Perm()), 8)
+	case OctalGo:
+		return "0o" + strconv.FormatUint(uint64(m.Perm()), 8)
+	case OctalFull:
+		return fmt.Sprintf("%06o", uint16(m))
+	}
+	return fmt.Sprintf("%04o", uint16(m.Perm()))
+}
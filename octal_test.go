@@ -0,0 +1,51 @@
+package unixmode_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleParseOctal() {
+	for _, in := range []string{"755", "0755", "0o755", "4755", "100644", "040755"} {
+		m, _ := unixmode.ParseOctal(in)
+		fmt.Printf("%-7s %s %s %s\n", in, m.PermString(), m.Octal(unixmode.OctalChmod), m.Octal(unixmode.OctalFull))
+	}
+	// Output:
+	// 755     rwxr-xr-x 0755 000755
+	// 0755    rwxr-xr-x 0755 000755
+	// 0o755   rwxr-xr-x 0755 000755
+	// 4755    rwsr-xr-x 4755 004755
+	// 100644  rw-r--r-- 0644 100644
+	// 040755  rwxr-xr-x 0755 040755
+}
+
+func ExampleParseOctal_invalid() {
+	for _, in := range []string{"", "0o", "789", "200000", "170644"} {
+		_, err := unixmode.ParseOctal(in)
+		fmt.Println(errors.Is(err, unixmode.ErrorOctal), errors.Is(err, unixmode.ErrorModeRange), errors.Is(err, unixmode.ErrorModeType), err)
+	}
+	_, err := unixmode.ParseOctalPerm("100644")
+	fmt.Println(err)
+	// Output:
+	// true false false Invalid Octal Mode: "" is empty
+	// true false false Invalid Octal Mode: "0o" has non-octal digit 'o'
+	// true false false Invalid Octal Mode: "789" has non-octal digit '8'
+	// false true false Mode Out Of Range: "200000" exceeds 177777
+	// false false true Invalid Mode Type: "170644" has type bits 170000
+	// Mode Out Of Range: "100644" exceeds 7777
+}
+
+func ExampleMode_Octal() {
+	m := unixmode.Mode(02750 | unixmode.ModeDir)
+	fmt.Println(m.Octal(unixmode.OctalChmod))
+	fmt.Println(m.Octal(unixmode.OctalStat))
+	fmt.Println(m.Octal(unixmode.OctalGo))
+	fmt.Println(m.Octal(unixmode.OctalFull))
+	// Output:
+	// 2750
+	// 2750
+	// 0o2750
+	// 042750
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import "strings"
+
+// An RsyncChmod is a compiled rsync(1) --chmod value, such as
+// "Dg+s,ug+w,Fo-w,+X".  It is a comma separated list of chmod(1) symbolic
+// clauses, each of which may be prefixed by 'D' to apply to directories only
+// or 'F' to apply to everything but directories.  A clause may also be an
+// octal number, like "D2775,F664", which sets all the permission bits.
+type RsyncChmod struct {
+	expr  string
+	rules []rsyncRule
+}
+
+type rsyncRule struct {
+	dirs, files bool
+	sym         *Symbolic // nil when set is used
+	set         Mode
+}
+
+// ParseRsyncChmod compiles an rsync --chmod value.  Errors are a
+// *SymbolicError with the Offset into the whole expression.
+func ParseRsyncChmod(expr string) (*RsyncChmod, error) {
+	r := &RsyncChmod{expr: expr}
+	off := 0
+	for _, raw := range strings.Split(expr, ",") {
+		rule := rsyncRule{dirs: true, files: true}
+		clause, start := raw, off
+		switch {
+		case strings.HasPrefix(clause, "D"):
+			rule.files = false
+			clause, start = clause[1:], start+1
+		case strings.HasPrefix(clause, "F"):
+			rule.dirs = false
+			clause, start = clause[1:], start+1
+		}
+		if clause != "" && strings.Trim(clause, "01234567") == "" {
+			m, err := ParseOctalPerm(clause)
+			if err != nil {
+				return nil, &SymbolicError{Expr: expr, Offset: start, Reason: err.Error()}
+			}
+			rule.set = m
+		} else {
+			s, err := ParseSymbolic(clause)
+			if err != nil {
+				se := err.(*SymbolicError)
+				return nil, &SymbolicError{Expr: expr, Offset: start + se.Offset, Reason: se.Reason}
+			}
+			rule.sym = s
+		}
+		r.rules = append(r.rules, rule)
+		off += len(raw) + 1
+	}
+	return r, nil
+}
+
+// String returns the expression the RsyncChmod was compiled from.
+func (r *RsyncChmod) String() string {
+	return r.expr
+}
+
+// Apply runs the clauses which match the type of m in order and returns the
+// result.  As in rsync, symbolic links are returned unchanged.
+func (r *RsyncChmod) Apply(m Mode) Mode {
+	if m.Type() == ModeSymlink {
+		return m
+	}
+	isDir := m.Type() == ModeDir
+	for _, rule := range r.rules {
+		if isDir && !rule.dirs || !isDir && !rule.files {
+			continue
+		}
+		if rule.sym != nil {
+			m = rule.sym.Apply(m)
+		} else {
+			m = m&^07777 | rule.set
+		}
+	}
+	return m
+}
+
+// RsyncOptions are the rsync(1) options which decide the mode of a file on
+// the receiving side.
+type RsyncOptions struct {
+	Perms         bool        // --perms, -p
+	Executability bool        // --executability, -E, ignored with Perms
+	Chmod         *RsyncChmod // --chmod, applied to the source mode first
+	Umask         Umask       // umask of the receiver, used for new files without Perms
+}
+
+// DestMode returns the mode rsync gives the destination, given the source
+// mode and, when exists is true, the mode of the file already at the
+// destination.  With Perms the source mode is used.  Without it an existing
+// file keeps its permissions and a new file gets the source permissions less
+// the Umask and the setuid, setgid, and sticky bits.  Executability then
+// makes an existing regular file match the source: execute is removed when
+// the source has none, and added where read is set when the destination has
+// none.
+func (o *RsyncOptions) DestMode(src, dst Mode, exists bool) Mode {
+	if o.Chmod != nil {
+		src = o.Chmod.Apply(src)
+	}
+	if o.Perms {
+		return src
+	}
+	if !exists {
+		return src&^07777 | (src & 0777).Masked(o.Umask)
+	}
+	m := src&^07777 | dst&07777
+	if o.Executability && src.Type() == ModeRegular {
+		if src&execAll == 0 {
+			m &^= execAll
+		} else if dst&execAll == 0 {
+			m |= m & (ModeReadUser | ModeReadGroup | ModeReadOther) >> 2
+		}
+	}
+	return m
+}
@@ -0,0 +1,51 @@
+package unixmode_test
+
+import (
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleRsyncChmod_Apply() {
+	r, _ := unixmode.ParseRsyncChmod("Dg+s,ug+w,Fo-w,+X")
+	for _, m := range []unixmode.Mode{
+		unixmode.ModeDir | 0700,
+		unixmode.ModeRegular | 0644,
+		unixmode.ModeRegular | 0706,
+		unixmode.ModeSymlink | 0777,
+	} {
+		to := r.Apply(m)
+		fmt.Printf("%c%s -> %c%s\n", m.TypeLetter(), m.PermString(), to.TypeLetter(), to.PermString())
+	}
+
+	r, _ = unixmode.ParseRsyncChmod("D2775,F664")
+	fmt.Println(r.Apply(unixmode.ModeDir|0700).Octal(unixmode.OctalFull), r.Apply(unixmode.ModeRegular|04755).Octal(unixmode.OctalFull))
+
+	_, err := unixmode.ParseRsyncChmod("Dg+s,Fo-q")
+	fmt.Println(err)
+	// Output:
+	// drwx------ -> drwx-ws--x
+	// -rw-r--r-- -> -rw-rw-r--
+	// -rwx---rw- -> -rwx-wxr-x
+	// lrwxrwxrwx -> lrwxrwxrwx
+	// 042775 100664
+	// Invalid Symbolic Mode "Dg+s,Fo-q" at offset 8: unexpected 'q', expected one of "rwxXst,+-="
+}
+
+func ExampleRsyncOptions_DestMode() {
+	src := unixmode.Mode(unixmode.ModeRegular | 04755)
+	dst := unixmode.Mode(unixmode.ModeRegular | 0640)
+	for _, o := range []unixmode.RsyncOptions{
+		{Perms: true},
+		{Umask: 022},
+		{},
+		{Executability: true},
+	} {
+		fmt.Println(o.DestMode(src, dst, false).Octal(unixmode.OctalFull), o.DestMode(src, dst, true).Octal(unixmode.OctalFull))
+	}
+	// Output:
+	// 104755 104755
+	// 100755 100640
+	// 100755 100640
+	// 100755 100750
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// XattrSELinux is the extended attribute holding the SELinux context.
+const XattrSELinux = "security.selinux"
+
+var (
+	ErrorSecurityContext   = errors.New("Invalid Security Context")
+	ErrorNoSecurityContext = errors.New("No Security Context")
+	ErrorLabelsUnsupported = errors.New("Security Labels Not Supported")
+)
+
+// A SecurityContext is an SELinux label, user:role:type:level.  The level is
+// optional and may itself hold colons, like "s0-s0:c0.c1023".
+type SecurityContext struct {
+	User  string
+	Role  string
+	Type  string
+	Level string
+}
+
+// ParseSecurityContext splits a context such as
+// "system_u:object_r:etc_t:s0" into its fields.  A trailing NUL, as stored in
+// the extended attribute, is ignored.
+func ParseSecurityContext(s string) (*SecurityContext, error) {
+	s = strings.TrimRight(s, "\x00")
+	f := strings.SplitN(s, ":", 4)
+	if len(f) < 3 {
+		return nil, fmt.Errorf("%w: %q needs at least user:role:type", ErrorSecurityContext, s)
+	}
+	for _, v := range f {
+		if v == "" || strings.ContainsAny(v, " \t\n\x00") {
+			return nil, fmt.Errorf("%w: %q has an empty or invalid field", ErrorSecurityContext, s)
+		}
+	}
+	c := &SecurityContext{User: f[0], Role: f[1], Type: f[2]}
+	if len(f) == 4 {
+		c.Level = f[3]
+	}
+	return c, nil
+}
+
+// String joins the fields back into the user:role:type:level form.
+func (c *SecurityContext) String() string {
+	s := c.User + ":" + c.Role + ":" + c.Type
+	if c.Level != "" {
+		s += ":" + c.Level
+	}
+	return s
+}
+
+// StringWithContext returns the mode string with '.' in the 11th character
+// and the context after it, as the first columns of ls -lZ show them, like
+// "-rw-r--r--. system_u:object_r:etc_t:s0".  A nil context prints as '?'.
+func (m Mode) StringWithContext(x Extras, c *SecurityContext) string {
+	if c == nil {
+		return m.StringWithExtras(x) + " ?"
+	}
+	return m.StringWithExtras(x|ExtraSecurityContext) + " " + c.String()
+}
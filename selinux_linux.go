@@ -0,0 +1,130 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"fmt"
+	"io/fs"
+	"os/user"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// GetSecurityContext reads the SELinux context of a file, following
+// symbolic links.  The label is read straight from the extended attribute,
+// so no SELinux policy needs to be loaded.  Errors wrap
+// ErrorNoSecurityContext when the file has no label and
+// ErrorLabelsUnsupported when the file system cannot store one.
+func GetSecurityContext(name string) (*SecurityContext, error) {
+	return getContext("getfilecon", name, getxattr)
+}
+
+// LgetSecurityContext is GetSecurityContext without following a trailing
+// symbolic link.
+func LgetSecurityContext(name string) (*SecurityContext, error) {
+	return getContext("lgetfilecon", name, lgetxattr)
+}
+
+func getContext(op, name string, get func(string, string) ([]byte, error)) (*SecurityContext, error) {
+	b, err := get(name, XattrSELinux)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: labelError(err)}
+	}
+	c, err := ParseSecurityContext(string(b))
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	return c, nil
+}
+
+// SetSecurityContext writes the SELinux context of a file.  With SELinux
+// enabled the policy decides whether the change is allowed, without it the
+// label is stored as is, which needs CAP_SYS_ADMIN.
+func SetSecurityContext(name string, c *SecurityContext) error {
+	if _, err := ParseSecurityContext(c.String()); err != nil {
+		return &fs.PathError{Op: "setfilecon", Path: name, Err: err}
+	}
+	if err := syscall.Setxattr(name, XattrSELinux, append([]byte(c.String()), 0), 0); err != nil {
+		return &fs.PathError{Op: "setfilecon", Path: name, Err: labelError(err)}
+	}
+	return nil
+}
+
+// LabelsSupported reports whether the file system holding name can store
+// security labels.  A file without a label still counts as supported, any
+// failure other than ENOTSUP is returned as an error.
+func LabelsSupported(name string) (bool, error) {
+	_, err := lgetxattr(name, XattrSELinux)
+	switch err {
+	case nil, syscall.ENODATA:
+		return true, nil
+	case syscall.ENOTSUP:
+		return false, nil
+	}
+	return false, &fs.PathError{Op: "lgetxattr", Path: name, Err: err}
+}
+
+func labelError(err error) error {
+	switch err {
+	case syscall.ENODATA:
+		return ErrorNoSecurityContext
+	case syscall.ENOTSUP:
+		return fmt.Errorf("%w (%v)", ErrorLabelsUnsupported, err)
+	}
+	return err
+}
+
+// LongContextString returns a line for a file in the format of ls -lZ:
+//
+//	-rw-r--r--. 1 root root system_u:object_r:etc_t:s0 1024 Jan  2 15:04 name
+//
+// A file without a label shows '?' for the context.
+func LongContextString(name string) (string, error) {
+	st, err := Lstat(name)
+	if err != nil {
+		return "", err
+	}
+	x, err := PathExtras(name)
+	if err != nil {
+		return "", err
+	}
+	c, _ := LgetSecurityContext(name)
+
+	owner := strconv.FormatUint(uint64(st.Uid), 10)
+	if u, err := user.LookupId(owner); err == nil {
+		owner = u.Username
+	}
+	group := strconv.FormatUint(uint64(st.Gid), 10)
+	if g, err := user.LookupGroupId(group); err == nil {
+		group = g.Name
+	}
+	size := strconv.FormatInt(st.Size, 10)
+	if st.Mode.Type() == ModeDevice || st.Mode.Type() == ModeCharDevice {
+		size = fmt.Sprintf("%d, %d", DevMajor(st.Rdev), DevMinor(st.Rdev))
+	}
+	// Like ls, show the year instead of the time for files older than six
+	// months or in the future
+	layout := "Jan _2 15:04"
+	if age := time.Since(st.Mtime); age < 0 || age > 182*24*time.Hour {
+		layout = "Jan _2  2006"
+	}
+	ctx := "?"
+	if c != nil {
+		ctx, x = c.String(), x|ExtraSecurityContext
+	}
+	return fmt.Sprintf("%s %d %s %s %s %s %s %s", st.Mode.StringWithExtras(x), st.Nlink,
+		owner, group, ctx, size, st.Mtime.Format(layout), name), nil
+}
@@ -0,0 +1,43 @@
+package unixmode_test
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func TestSetSecurityContext(t *testing.T) {
+	f, err := os.CreateTemp("", "label")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	const label = "system_u:object_r:tmp_t:s0"
+	c, err := unixmode.ParseSecurityContext(label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = unixmode.SetSecurityContext(f.Name(), c)
+	if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOTSUP) || errors.Is(err, unixmode.ErrorLabelsUnsupported) {
+		t.Skip("cannot write security.selinux:", err)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	if c, err = unixmode.GetSecurityContext(f.Name()); err != nil || c.String() != label {
+		t.Errorf("GetSecurityContext = %v, %v, want %s", c, err, label)
+	}
+	if s, err := unixmode.LongString(f.Name()); err != nil || s != "-rw-------." {
+		t.Errorf("LongString = %q, %v, want %q", s, err, "-rw-------.")
+	}
+}
+
+func TestLabelsSupported(t *testing.T) {
+	if _, err := unixmode.LabelsSupported("/nonexistent"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LabelsSupported on a missing file returned %v, want a not exist error", err)
+	}
+}
@@ -0,0 +1,19 @@
+package unixmode_test
+
+import (
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleParseSecurityContext() {
+	c, err := unixmode.ParseSecurityContext("system_u:object_r:shadow_t:s0-s0:c0.c1023\x00")
+	fmt.Printf("%s|%s|%s|%s %v\n", c.User, c.Role, c.Type, c.Level, err)
+	fmt.Println(unixmode.Mode(0100640).StringWithContext(0, c))
+	_, err = unixmode.ParseSecurityContext("system_u:object_r")
+	fmt.Println(err)
+	// Output:
+	// system_u|object_r|shadow_t|s0-s0:c0.c1023 <nil>
+	// -rw-r-----. system_u:object_r:shadow_t:s0-s0:c0.c1023
+	// Invalid Security Context: "system_u:object_r" needs at least user:role:type
+}
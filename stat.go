@@ -0,0 +1,49 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import "time"
+
+// A FileStat holds the fields of a POSIX stat block which describe the
+// ownership and identity of a file, with the Mode taken from st_mode as-is
+// rather than through the Go portable fs.FileMode bits.
+type FileStat struct {
+	Mode  Mode
+	Uid   uint32
+	Gid   uint32
+	Nlink uint64
+	Ino   uint64
+	Dev   uint64 // device holding the file
+	Rdev  uint64 // device number, for ModeDevice and ModeCharDevice files
+	Size  int64
+	Mtime time.Time
+}
+
+// DevMajor returns the major number of a Linux device number, such as
+// FileStat.Rdev.
+func DevMajor(dev uint64) uint32 {
+	return uint32(dev>>8&0xfff | dev>>32&^0xfff)
+}
+
+// DevMinor returns the minor number of a Linux device number.
+func DevMinor(dev uint64) uint32 {
+	return uint32(dev&0xff | dev>>12&^0xff)
+}
+
+// MakeDev builds a Linux device number from the major and minor numbers.
+func MakeDev(major, minor uint32) uint64 {
+	return uint64(major&0xfff)<<8 | uint64(major&^0xfff)<<32 |
+		uint64(minor&0xff) | uint64(minor&^0xff)<<12
+}
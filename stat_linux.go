@@ -0,0 +1,70 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// FromStat returns the Mode held in the st_mode field of a stat block.
+func FromStat(st *syscall.Stat_t) Mode {
+	return Mode(st.Mode)
+}
+
+// NewFileStat copies the interesting fields of a stat block into a FileStat.
+func NewFileStat(st *syscall.Stat_t) *FileStat {
+	return &FileStat{
+		Mode:  Mode(st.Mode),
+		Uid:   st.Uid,
+		Gid:   st.Gid,
+		Nlink: uint64(st.Nlink),
+		Ino:   uint64(st.Ino),
+		Dev:   uint64(st.Dev),
+		Rdev:  uint64(st.Rdev),
+		Size:  int64(st.Size),
+		Mtime: time.Unix(int64(st.Mtim.Sec), int64(st.Mtim.Nsec)),
+	}
+}
+
+// FileInfoStat returns the FileStat backing a fs.FileInfo from os.Stat,
+// os.Lstat, or a directory listing.  The boolean is false when the FileInfo
+// did not come from the local file system.
+func FileInfoStat(fi fs.FileInfo) (*FileStat, bool) {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return NewFileStat(st), true
+	}
+	return nil, false
+}
+
+// Stat calls stat(2) on name, following symbolic links.
+func Stat(name string) (*FileStat, error) {
+	var st syscall.Stat_t
+	if err := syscall.Stat(name, &st); err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return NewFileStat(&st), nil
+}
+
+// Lstat calls lstat(2) on name, a symbolic link is described rather than
+// followed.
+func Lstat(name string) (*FileStat, error) {
+	var st syscall.Stat_t
+	if err := syscall.Lstat(name, &st); err != nil {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: err}
+	}
+	return NewFileStat(&st), nil
+}
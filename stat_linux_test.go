@@ -0,0 +1,27 @@
+package unixmode_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleLstat() {
+	f, _ := os.CreateTemp("", "lstat")
+	f.Close()
+	defer os.Remove(f.Name())
+	unixmode.Chmod(f.Name(), 02640)
+
+	st, err := unixmode.Lstat(f.Name())
+	fmt.Printf("%v %q %s %d\n", err, st.Mode, st.Mode.Octal(unixmode.OctalFull), st.Nlink)
+	// Output:
+	// <nil> "-rw-r-S--- " 102640 1
+}
+
+func ExampleMakeDev() {
+	dev := unixmode.MakeDev(259, 65536)
+	fmt.Printf("%#x %d %d\n", dev, unixmode.DevMajor(dev), unixmode.DevMinor(dev))
+	// Output:
+	// 0x10010300 259 65536
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import "strings"
+
+// StatxAttr holds the stx_attributes bits returned by statx(2).
+type StatxAttr uint64
+
+const (
+	StatxAttrCompressed StatxAttr = 0x00000004 /* compressed by the file system */
+	StatxAttrImmutable  StatxAttr = 0x00000010 /* cannot be modified (chattr +i) */
+	StatxAttrAppend     StatxAttr = 0x00000020 /* can only be appended to (chattr +a) */
+	StatxAttrNodump     StatxAttr = 0x00000040 /* not to be backed up by dump (chattr +d) */
+	StatxAttrEncrypted  StatxAttr = 0x00000800 /* needs a key to be decrypted */
+	StatxAttrAutomount  StatxAttr = 0x00001000 /* directory is an automount trigger */
+	StatxAttrMountRoot  StatxAttr = 0x00002000 /* root of a mount */
+	StatxAttrVerity     StatxAttr = 0x00100000 /* protected by fs-verity */
+	StatxAttrDax        StatxAttr = 0x00200000 /* in the CPU direct access state */
+)
+
+var statxAttrNames = []struct {
+	attr StatxAttr
+	name string
+}{
+	{StatxAttrCompressed, "compressed"},
+	{StatxAttrImmutable, "immutable"},
+	{StatxAttrAppend, "append"},
+	{StatxAttrNodump, "nodump"},
+	{StatxAttrEncrypted, "encrypted"},
+	{StatxAttrAutomount, "automount"},
+	{StatxAttrMountRoot, "mount-root"},
+	{StatxAttrVerity, "verity"},
+	{StatxAttrDax, "dax"},
+}
+
+// String returns the names of the set attributes separated by commas, such
+// as "immutable,append".
+func (a StatxAttr) String() string {
+	var s []string
+	for _, n := range statxAttrNames {
+		if a&n.attr != 0 {
+			s = append(s, n.name)
+		}
+	}
+	return strings.Join(s, ",")
+}
+
+// StringWithAttributes returns String followed by the attribute names in
+// brackets when any are set, like "-rw-r--r--  [immutable,append]".
+func (m Mode) StringWithAttributes(a StatxAttr) string {
+	if a == 0 {
+		return m.String()
+	}
+	return m.String() + " [" + a.String() + "]"
+}
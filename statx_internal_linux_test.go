@@ -0,0 +1,13 @@
+package unixmode
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestStatxSize(t *testing.T) {
+	// sizeof(struct statx) in <linux/stat.h>
+	if n := unsafe.Sizeof(statxT{}); n != 256 {
+		t.Errorf("statxT is %d bytes, want 256", n)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"io/fs"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Flags for Statx, to be combined with AtSymlinkNoFollow.
+const (
+	AtNoAutomount    = 0x800  /* do not trigger an automount */
+	AtStatxForceSync = 0x2000 /* sync a remote file system before reading */
+	AtStatxDontSync  = 0x4000 /* use cached attributes of a remote file system */
+)
+
+// The statx(2) syscall number, which is not in the syscall package for most
+// architectures.
+var sysStatx = map[string]uintptr{
+	"386":      383,
+	"amd64":    332,
+	"arm":      397,
+	"arm64":    291,
+	"loong64":  291,
+	"mips":     4366,
+	"mipsle":   4366,
+	"mips64":   5326,
+	"mips64le": 5326,
+	"ppc64":    383,
+	"ppc64le":  383,
+	"riscv64":  291,
+	"s390x":    379,
+}[runtime.GOARCH]
+
+const (
+	statxBasicStats = 0x7ff  /* STATX_BASIC_STATS */
+	statxBtime      = 0x800  /* STATX_BTIME */
+	statxMntID      = 0x1000 /* STATX_MNT_ID */
+)
+
+type statxTimestamp struct {
+	Sec  int64
+	Nsec uint32
+	_    int32
+}
+
+// statxT mirrors struct statx from <linux/stat.h>.
+type statxT struct {
+	Mask           uint32
+	Blksize        uint32
+	Attributes     uint64
+	Nlink          uint32
+	Uid            uint32
+	Gid            uint32
+	Mode           uint16
+	_              uint16
+	Ino            uint64
+	Size           uint64
+	Blocks         uint64
+	AttributesMask uint64
+	Atime          statxTimestamp
+	Btime          statxTimestamp
+	Ctime          statxTimestamp
+	Mtime          statxTimestamp
+	RdevMajor      uint32
+	RdevMinor      uint32
+	DevMajor       uint32
+	DevMinor       uint32
+	MntID          uint64
+	_              [13]uint64 // stx_dio_mem_align and the spare fields
+}
+
+// StatxInfo is the result of Statx: the FileStat fields together with the
+// inode attributes, the mount ID, and the birth time.
+type StatxInfo struct {
+	FileStat
+
+	// Attributes are the attributes set on the file, AttributesMask tells
+	// which ones the file system supports at all.
+	Attributes     StatxAttr
+	AttributesMask StatxAttr
+
+	MountID uint64    // zero when the kernel does not report it
+	Btime   time.Time // zero when the file system does not record it
+}
+
+// Statx calls statx(2) on name, relative to the working directory.  The
+// flags are AtSymlinkNoFollow, AtNoAutomount, AtStatxForceSync, or
+// AtStatxDontSync.  The mode and the inode attributes come back from a
+// single syscall.  Kernels before 4.11 return ENOSYS.
+func Statx(name string, flags int) (*StatxInfo, error) {
+	if sysStatx == 0 {
+		return nil, &fs.PathError{Op: "statx", Path: name, Err: syscall.ENOSYS}
+	}
+	p, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "statx", Path: name, Err: err}
+	}
+	var st statxT
+	dirfd := AtFDCWD
+	_, _, e := syscall.Syscall6(sysStatx, uintptr(dirfd), uintptr(unsafe.Pointer(p)), uintptr(flags),
+		statxBasicStats|statxBtime|statxMntID, uintptr(unsafe.Pointer(&st)), 0)
+	if e != 0 {
+		return nil, &fs.PathError{Op: "statx", Path: name, Err: e}
+	}
+
+	info := &StatxInfo{
+		FileStat: FileStat{
+			Mode:  Mode(st.Mode),
+			Uid:   st.Uid,
+			Gid:   st.Gid,
+			Nlink: uint64(st.Nlink),
+			Ino:   st.Ino,
+			Dev:   MakeDev(st.DevMajor, st.DevMinor),
+			Rdev:  MakeDev(st.RdevMajor, st.RdevMinor),
+			Size:  int64(st.Size),
+			Mtime: time.Unix(st.Mtime.Sec, int64(st.Mtime.Nsec)),
+		},
+		Attributes:     StatxAttr(st.Attributes),
+		AttributesMask: StatxAttr(st.AttributesMask),
+	}
+	if st.Mask&statxMntID != 0 {
+		info.MountID = st.MntID
+	}
+	if st.Mask&statxBtime != 0 {
+		info.Btime = time.Unix(st.Btime.Sec, int64(st.Btime.Nsec))
+	}
+	return info, nil
+}
+
+// String returns the mode string annotated with the attributes, as
+// Mode.StringWithAttributes.
+func (s *StatxInfo) String() string {
+	return s.Mode.StringWithAttributes(s.Attributes)
+}
@@ -0,0 +1,28 @@
+package unixmode_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleStatx() {
+	f, _ := os.CreateTemp("", "statx")
+	f.Close()
+	defer os.Remove(f.Name())
+	unixmode.Chmod(f.Name(), 0640)
+
+	info, err := unixmode.Statx(f.Name(), unixmode.AtSymlinkNoFollow)
+	st, _ := unixmode.Lstat(f.Name())
+	fmt.Printf("%v %q %v %v\n", err, info.String(), info.Ino == st.Ino, info.Dev == st.Dev)
+	// Output:
+	// <nil> "-rw-r----- " true true
+}
+
+func ExampleMode_StringWithAttributes() {
+	m := unixmode.Mode(0644 | unixmode.ModeRegular)
+	fmt.Println(m.StringWithAttributes(unixmode.StatxAttrImmutable | unixmode.StatxAttrVerity))
+	// Output:
+	// -rw-r--r--  [immutable,verity]
+}
@@ -0,0 +1,241 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// A Symbolic is a compiled chmod(1) symbolic mode expression, such as
+// "u+x,g-w,o=r" or "a+X".  The grammar follows POSIX:
+//
+//	symbolic : clause [, clause ...]
+//	clause   : [who ...] action [action ...]
+//	action   : op [perm ...] | op copy
+//	who      : u | g | o | a
+//	op       : + | - | =
+//	perm     : r | w | x | X | s | t
+//	copy     : u | g | o
+//
+// When no who letter is given the clause acts as if 'a' was given.  Unlike
+// chmod(1) the process umask is not consulted, so "+w" grants write to
+// everyone.
+type Symbolic struct {
+	expr string
+	ops  []symbolicOp
+}
+
+type symbolicOp struct {
+	who   Mode // bits affected, including the special bits for the class
+	op    byte // '+', '-' or '='
+	perm  Mode // bits to add, remove, or set
+	condX bool // 'X' was given, exec is granted conditionally
+	copy  Mode // class mask to copy from ('u', 'g', or 'o'), zero if unset
+}
+
+// The who classes and the bits each one is allowed to touch.
+const (
+	symbolicUser  = ModeSetuid | ModeUserMask
+	symbolicGroup = ModeSetgid | ModeGroupMask
+	symbolicOther = ModeSticky | ModeOtherMask
+	symbolicAll   = symbolicUser | symbolicGroup | symbolicOther
+	execAll       = ModeExecUser | ModeExecGroup | ModeExecOther
+)
+
+// ErrorSymbolic is wrapped by every error returned from ParseSymbolic.
+var ErrorSymbolic = errors.New("Invalid Symbolic Mode")
+
+// SymbolicError describes where a symbolic mode expression failed to parse.
+type SymbolicError struct {
+	Expr   string // the full expression
+	Offset int    // byte offset into Expr where the error was found
+	Reason string
+}
+
+func (e *SymbolicError) Error() string {
+	return fmt.Sprintf("%s %q at offset %d: %s", ErrorSymbolic, e.Expr, e.Offset, e.Reason)
+}
+
+// Unwrap returns ErrorSymbolic so errors.Is can be used on the result.
+func (e *SymbolicError) Unwrap() error { return ErrorSymbolic }
+
+// ParseSymbolic compiles a chmod(1) style symbolic mode expression.
+func ParseSymbolic(expr string) (*Symbolic, error) {
+	s := &Symbolic{expr: expr}
+	fail := func(i int, format string, a ...interface{}) (*Symbolic, error) {
+		return nil, &SymbolicError{Expr: expr, Offset: i, Reason: fmt.Sprintf(format, a...)}
+	}
+	if len(expr) == 0 {
+		return fail(0, "empty expression")
+	}
+
+	i := 0
+	for {
+		// Collect the who list
+		var who Mode
+	who:
+		for ; i < len(expr); i++ {
+			switch expr[i] {
+			case 'u':
+				who |= symbolicUser
+			case 'g':
+				who |= symbolicGroup
+			case 'o':
+				who |= symbolicOther
+			case 'a':
+				who |= symbolicAll
+			default:
+				break who
+			}
+		}
+		if who == 0 {
+			who = symbolicAll
+		}
+		if i >= len(expr) || !isSymbolicOp(expr[i]) {
+			if i >= len(expr) {
+				return fail(i, "missing operator, expected one of \"+-=\"")
+			}
+			return fail(i, "unexpected %q, expected one of \"ugoa+-=\"", expr[i])
+		}
+
+		// Each clause may hold several actions, like "u+r-w"
+		for i < len(expr) && isSymbolicOp(expr[i]) {
+			op := symbolicOp{who: who, op: expr[i]}
+			i++
+			if i < len(expr) {
+				switch expr[i] {
+				case 'u':
+					op.copy = ModeUserMask
+					i++
+				case 'g':
+					op.copy = ModeGroupMask
+					i++
+				case 'o':
+					op.copy = ModeOtherMask
+					i++
+				}
+			}
+			if op.copy == 0 {
+			perms:
+				for ; i < len(expr); i++ {
+					switch expr[i] {
+					case 'r':
+						op.perm |= ModeReadUser | ModeReadGroup | ModeReadOther
+					case 'w':
+						op.perm |= ModeWriteUser | ModeWriteGroup | ModeWriteOther
+					case 'x':
+						op.perm |= execAll
+					case 'X':
+						op.condX = true
+					case 's':
+						op.perm |= ModeSetuid | ModeSetgid
+					case 't':
+						op.perm |= ModeSticky
+					default:
+						break perms
+					}
+				}
+			}
+			op.perm &= who
+			s.ops = append(s.ops, op)
+		}
+
+		if i == len(expr) {
+			return s, nil
+		}
+		switch expr[i] {
+		case ',':
+			i++
+			if i == len(expr) {
+				return fail(i, "trailing comma")
+			}
+		case 'u', 'g', 'o':
+			return fail(i, "copy class %q must directly follow an operator", expr[i])
+		default:
+			return fail(i, "unexpected %q, expected one of \"rwxXst,+-=\"", expr[i])
+		}
+	}
+}
+
+func isSymbolicOp(c byte) bool {
+	return c == '+' || c == '-' || c == '='
+}
+
+// String returns the expression the Symbolic was compiled from.
+func (s *Symbolic) String() string {
+	return s.expr
+}
+
+// Apply runs the expression against m and returns the result.  The file type
+// bits are preserved and are used to decide whether 'X' grants execute.
+func (s *Symbolic) Apply(m Mode) Mode {
+	typ, perm := m&ModeTypeMask, m&07777
+	for _, op := range s.ops {
+		val := op.perm
+		if op.copy != 0 {
+			// Replicate the source class into all three classes
+			c := perm & op.copy
+			switch op.copy {
+			case ModeUserMask:
+				c >>= 6
+			case ModeGroupMask:
+				c >>= 3
+			}
+			val = (c<<6 | c<<3 | c) & op.who
+		}
+		if op.condX && (typ == ModeDir || perm&execAll != 0) {
+			val |= execAll & op.who
+		}
+		switch op.op {
+		case '+':
+			perm |= val
+		case '-':
+			perm &^= val
+		case '=':
+			perm = perm&^op.who | val
+		}
+	}
+	return typ | perm
+}
+
+// ApplyFileMode runs the expression against a fs.FileMode.  Bits which have
+// no POSIX equivalent, such as fs.ModeAppend, are passed through untouched.
+func (s *Symbolic) ApplyFileMode(m fs.FileMode) fs.FileMode {
+	const posix = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+	return s.Apply(New(m)).FileMode()&posix | m&^posix
+}
+
+// Apply parses the symbolic expression and applies it to m, for example:
+//
+//	m, err := unixmode.Mode(0644).Apply("u+x,g=u,o-r")
+func (m Mode) Apply(expr string) (Mode, error) {
+	s, err := ParseSymbolic(expr)
+	if err != nil {
+		return m, err
+	}
+	return s.Apply(m), nil
+}
+
+// FileModeApply parses the symbolic expression and applies it to the
+// fs.FileMode m.
+func FileModeApply(m fs.FileMode, expr string) (fs.FileMode, error) {
+	s, err := ParseSymbolic(expr)
+	if err != nil {
+		return m, err
+	}
+	return s.ApplyFileMode(m), nil
+}
@@ -0,0 +1,57 @@
+package unixmode_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleMode_Apply() {
+	m := unixmode.Mode(0644 | unixmode.ModeRegular)
+	for _, expr := range []string{"u+x,g-w,o=r", "a+X", "go=u", "u=rwx,g=rx,o=", "ug+s,+t", "u+rw-x", "o+s"} {
+		out, err := m.Apply(expr)
+		fmt.Printf("%-14s %s %04o %v\n", expr, out, out.Perm(), err)
+	}
+	// Output:
+	// u+x,g-w,o=r    -rwxr--r--  0744 <nil>
+	// a+X            -rw-r--r--  0644 <nil>
+	// go=u           -rw-rw-rw-  0666 <nil>
+	// u=rwx,g=rx,o=  -rwxr-x---  0750 <nil>
+	// ug+s,+t        -rwSr-Sr-T  7644 <nil>
+	// u+rw-x         -rw-r--r--  0644 <nil>
+	// o+s            -rw-r--r--  0644 <nil>
+}
+
+func ExampleSymbolic_Apply() {
+	s, _ := unixmode.ParseSymbolic("a+X")
+	fmt.Println(s.Apply(0644 | unixmode.ModeDir).PermString())
+	fmt.Println(s.Apply(0744 | unixmode.ModeRegular).PermString())
+	fmt.Println(s.Apply(0644 | unixmode.ModeRegular).PermString())
+	// Output:
+	// rwxr-xr-x
+	// rwxr-xr-x
+	// rw-r--r--
+}
+
+func ExampleParseSymbolic_invalid() {
+	for _, expr := range []string{"", "u+z", "q+r", "u", "u+r,", "g+wu"} {
+		_, err := unixmode.ParseSymbolic(expr)
+		fmt.Println(errors.Is(err, unixmode.ErrorSymbolic), err)
+	}
+	// Output:
+	// true Invalid Symbolic Mode "" at offset 0: empty expression
+	// true Invalid Symbolic Mode "u+z" at offset 2: unexpected 'z', expected one of "rwxXst,+-="
+	// true Invalid Symbolic Mode "q+r" at offset 0: unexpected 'q', expected one of "ugoa+-="
+	// true Invalid Symbolic Mode "u" at offset 1: missing operator, expected one of "+-="
+	// true Invalid Symbolic Mode "u+r," at offset 4: trailing comma
+	// true Invalid Symbolic Mode "g+wu" at offset 3: copy class 'u' must directly follow an operator
+}
+
+func ExampleFileModeApply() {
+	fm, _ := unixmode.FileModeApply(fs.ModeDir|fs.ModeAppend|0700, "g+rXs,o+t")
+	fmt.Println(fm)
+	// Output:
+	// dagtrwxr-x---
+}
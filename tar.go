@@ -0,0 +1,147 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorTarHeader is wrapped by every TarHeaderError.
+var ErrorTarHeader = errors.New("Inconsistent Tar Header")
+
+// TarHeaderError describes a tar header whose Typeflag and Mode disagree, or
+// which cannot hold the requested Mode.
+type TarHeaderError struct {
+	Name     string // the header Name
+	Typeflag byte
+	Mode     int64
+	Reason   string
+}
+
+func (e *TarHeaderError) Error() string {
+	return fmt.Sprintf("%s %q: typeflag %q mode %06o: %s", ErrorTarHeader, e.Name, e.Typeflag, e.Mode, e.Reason)
+}
+
+// Unwrap returns ErrorTarHeader so errors.Is can be used on the result.
+func (e *TarHeaderError) Unwrap() error { return ErrorTarHeader }
+
+// tarType returns the file type a Typeflag stands for, or zero when the entry
+// is not a file, like a PAX or GNU long name header.
+func tarType(h *tar.Header) Mode {
+	switch h.Typeflag {
+	case tar.TypeReg, tar.TypeLink, tar.TypeGNUSparse:
+		return ModeRegular
+	case tar.TypeRegA:
+		// Old archives mark directories with a trailing slash only
+		if strings.HasSuffix(h.Name, "/") {
+			return ModeDir
+		}
+		return ModeRegular
+	case tar.TypeCont:
+		return ModeContiguous
+	case tar.TypeSymlink:
+		return ModeSymlink
+	case tar.TypeChar:
+		return ModeCharDevice
+	case tar.TypeBlock:
+		return ModeDevice
+	case tar.TypeDir:
+		return ModeDir
+	case tar.TypeFifo:
+		return ModeNamedPipe
+	}
+	return 0
+}
+
+// FromTarHeader returns the Mode of a tar entry.  The permission, setuid,
+// setgid, and sticky bits come from h.Mode and the file type from
+// h.Typeflag.  Archives written by older tools may also carry the st_mode
+// type bits in h.Mode, these must agree with the Typeflag.  A hard link
+// (TypeLink) takes its type from h.Mode when present, as the link may point
+// at any non-directory.
+func FromTarHeader(h *tar.Header) (Mode, error) {
+	fail := func(reason string) (Mode, error) {
+		return 0, &TarHeaderError{Name: h.Name, Typeflag: h.Typeflag, Mode: h.Mode, Reason: reason}
+	}
+	if h.Mode < 0 || h.Mode > 0177777 {
+		return fail("mode out of range")
+	}
+	typ := tarType(h)
+	if typ == 0 {
+		return fail("not a file entry")
+	}
+	m := Mode(h.Mode)
+	if !m.validType() {
+		return fail("unknown type bits")
+	}
+	switch bits := m & ModeTypeMask; {
+	case bits == 0, bits == typ:
+	case h.Typeflag == tar.TypeLink && bits != ModeDir:
+		typ = bits
+	case bits == ModeRegular && typ == ModeContiguous:
+		// Contiguous files are regular files to most systems
+	default:
+		return fail(fmt.Sprintf("type bits %06o contradict the typeflag", uint16(bits)))
+	}
+	return typ | m.Perm(), nil
+}
+
+// ApplyToTarHeader stores the Mode into h.  The permission, setuid, setgid,
+// and sticky bits are written to h.Mode, without any type bits, as
+// tar.FileInfoHeader does, and the type selects the Typeflag.  A Mode with no
+// type bits leaves the Typeflag alone.  A hard link (TypeLink) or GNU sparse
+// header is kept if the Mode allows it.  Sockets and the nonstandard types
+// other than ModeContiguous have no tar representation and are rejected.
+func (m Mode) ApplyToTarHeader(h *tar.Header) error {
+	fail := func(reason string) error {
+		return &TarHeaderError{Name: h.Name, Typeflag: h.Typeflag, Mode: int64(m), Reason: reason}
+	}
+	var flag byte
+	switch m.Type() {
+	case 0:
+		flag = h.Typeflag
+	case ModeRegular:
+		flag = tar.TypeReg
+		if h.Typeflag == tar.TypeGNUSparse {
+			flag = h.Typeflag
+		}
+	case ModeContiguous:
+		flag = tar.TypeCont
+	case ModeSymlink:
+		flag = tar.TypeSymlink
+	case ModeCharDevice:
+		flag = tar.TypeChar
+	case ModeDevice:
+		flag = tar.TypeBlock
+	case ModeDir:
+		flag = tar.TypeDir
+	case ModeNamedPipe:
+		flag = tar.TypeFifo
+	default:
+		return fail("file type has no tar typeflag")
+	}
+	if h.Typeflag == tar.TypeLink {
+		if m.Type() == ModeDir {
+			return fail("a directory cannot be a hard link")
+		}
+		flag = tar.TypeLink
+	}
+	h.Typeflag = flag
+	h.Mode = int64(m.Perm())
+	return nil
+}
@@ -0,0 +1,73 @@
+package unixmode_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleFromTarHeader() {
+	for _, h := range []*tar.Header{
+		{Name: "bin/sudo", Typeflag: tar.TypeReg, Mode: 04755},
+		{Name: "tmp/", Typeflag: tar.TypeDir, Mode: 041777},
+		{Name: "old/", Typeflag: tar.TypeRegA, Mode: 0755},
+		{Name: "dev/null", Typeflag: tar.TypeChar, Mode: 0666},
+		{Name: "hard", Typeflag: tar.TypeLink, Mode: 010644},
+	} {
+		m, err := unixmode.FromTarHeader(h)
+		fmt.Printf("%c%s %v\n", m.TypeLetter(), m.PermString(), err)
+	}
+
+	_, err := unixmode.FromTarHeader(&tar.Header{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0100644})
+	fmt.Println(err)
+	fmt.Println(errors.Is(err, unixmode.ErrorTarHeader))
+	// Output:
+	// -rwsr-xr-x <nil>
+	// drwxrwxrwt <nil>
+	// drwxr-xr-x <nil>
+	// crw-rw-rw- <nil>
+	// prw-r--r-- <nil>
+	// Inconsistent Tar Header "etc/": typeflag '5' mode 100644: type bits 100000 contradict the typeflag
+	// true
+}
+
+func ExampleMode_ApplyToTarHeader() {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, m := range []unixmode.Mode{
+		unixmode.ModeRegular | 02755,
+		unixmode.ModeSymlink | 0777,
+		unixmode.ModeDir | 01777,
+		unixmode.ModeNamedPipe | 0600,
+	} {
+		h := &tar.Header{Name: m.Octal(unixmode.OctalFull)}
+		m.ApplyToTarHeader(h)
+		tw.WriteHeader(h)
+	}
+	tw.Close()
+
+	tr := tar.NewReader(&buf)
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			break
+		}
+		m, _ := unixmode.FromTarHeader(h)
+		fmt.Printf("%s %c %04o %c%s\n", h.Name, h.Typeflag, h.Mode, m.TypeLetter(), m.PermString())
+	}
+
+	err := unixmode.Mode(unixmode.ModeSocket | 0755).ApplyToTarHeader(&tar.Header{Name: "sock"})
+	fmt.Println(err)
+	err = unixmode.Mode(unixmode.ModeDir | 0755).ApplyToTarHeader(&tar.Header{Name: "link", Typeflag: tar.TypeLink})
+	fmt.Println(err)
+	// Output:
+	// 102755 0 2755 -rwxr-sr-x
+	// 120777 2 0777 lrwxrwxrwx
+	// 041777 5 1777 drwxrwxrwt
+	// 010600 6 0600 prw-------
+	// Inconsistent Tar Header "sock": typeflag '\x00' mode 140755: file type has no tar typeflag
+	// Inconsistent Tar Header "link": typeflag '1' mode 040755: a directory cannot be a hard link
+}
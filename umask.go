@@ -0,0 +1,97 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// A Umask is the file mode creation mask of a process.  Bits which are set in
+// the Umask are cleared from the requested mode when a file or directory is
+// created.
+type Umask uint16
+
+// ErrorUmask is returned when the process umask cannot be determined.
+var ErrorUmask = errors.New("Unable To Read Umask")
+
+// String returns the Umask in the four digit format printed by umask(1).
+func (u Umask) String() string {
+	return fmt.Sprintf("%04o", uint16(u&0777))
+}
+
+// Masked returns m with the bits in the umask cleared.  Only the lower 9
+// permission bits of the umask are honored, as with umask(2).
+func (m Mode) Masked(u Umask) Mode {
+	return m &^ Mode(u&0777)
+}
+
+// CreateMode returns the Mode a new file or directory will have when it is
+// created with the requested mode under this umask.  Directories created by
+// mkdir(2) on Linux drop the setuid and setgid bits, the setgid bit may come
+// back if it is inherited from the parent directory.  The kernel may also
+// strip setgid from a new file when the caller is not in the owning group.
+func (u Umask) CreateMode(requested Mode, isDir bool) Mode {
+	if isDir {
+		return ModeDir | requested.Masked(u)&01777
+	}
+	return ModeRegular | requested.Masked(u)&07777
+}
+
+// PredictCreateMode reports the Mode os.OpenFile with O_CREATE or os.Mkdir
+// will actually produce when handed requested.FileMode(), given the current
+// process umask.
+func PredictCreateMode(requested Mode, isDir bool) (Mode, error) {
+	u, err := CurrentUmask()
+	if err != nil {
+		return 0, err
+	}
+	return u.CreateMode(requested, isDir), nil
+}
+
+// CurrentUmask returns the umask of the running process.  On Linux 4.7 and
+// later the value is read from the Umask field of /proc/self/status, which
+// leaves the umask untouched.  Elsewhere the umask must be read by setting it
+// and restoring it again, a file created by another goroutine in that window
+// gets the wrong mode.
+func CurrentUmask() (Umask, error) {
+	if u, err := procUmask("/proc/self/status"); err == nil {
+		return u, nil
+	}
+	return swapUmask()
+}
+
+// procUmask parses the "Umask:" line of a /proc/<pid>/status file.
+func procUmask(file string) (Umask, error) {
+	dat, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	sc := bufio.NewScanner(bytes.NewReader(dat))
+	for sc.Scan() {
+		if val := bytes.TrimPrefix(sc.Bytes(), []byte("Umask:")); len(val) < len(sc.Bytes()) {
+			v, err := strconv.ParseUint(string(bytes.TrimSpace(val)), 8, 16)
+			if err != nil || v > 0777 {
+				return 0, fmt.Errorf("%w: bad value %q in %s", ErrorUmask, val, file)
+			}
+			return Umask(v), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: no Umask field in %s", ErrorUmask, file)
+}
@@ -0,0 +1,33 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package unixmode
+
+import (
+	"sync"
+	"syscall"
+)
+
+var umaskLock sync.Mutex
+
+// swapUmask reads the umask with the umask(0) and restore sequence.
+func swapUmask() (Umask, error) {
+	umaskLock.Lock()
+	defer umaskLock.Unlock()
+	u := syscall.Umask(0)
+	syscall.Umask(u)
+	return Umask(u), nil
+}
@@ -0,0 +1,31 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package unixmode_test
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleCurrentUmask() {
+	old := syscall.Umask(027)
+	defer syscall.Umask(old)
+
+	u, err := unixmode.CurrentUmask()
+	fmt.Println(u, err)
+	// Output:
+	// 0027 <nil>
+}
+
+func ExampleUmask_CreateMode() {
+	u := unixmode.Umask(022)
+	fmt.Printf("%q\n", u.CreateMode(0666, false).String())
+	fmt.Printf("%q\n", u.CreateMode(06777, true).String())
+	fmt.Printf("%04o\n", unixmode.Mode(0777).Masked(077))
+	// Output:
+	// "-rw-r--r-- "
+	// "drwxr-xr-x "
+	// 0700
+}
@@ -53,8 +53,10 @@
 //      (will be retained in swap space after execution), '-'
 //      otherwise.
 //      'T' if the file is sticky but not executable.
-// 10   ' ' for compatibility with 4.4BSD strmode,
-//      since this interface does not support ACLs.
+// 10   ' ' for compatibility with 4.4BSD strmode, String always
+//      uses this.  StringWithExtras and LongString fill in '+' when
+//      the file has an extended ACL, or '.' when it only has a
+//      security context, as GNU ls does.
 //
 // The TypeLetter functions return a character indicating the type of file
 // described by file mode BITS:
@@ -66,7 +68,8 @@
 // - 'd' directory
 // - 'D' door***
 // - 'l' symbolic link
-// - 'm' multiplexed file (7th edition Unix; obsolete)***
+// - 'm' multiplexed character special file (7th edition Unix; obsolete)***
+// - 'M' multiplexed block special file (7th edition Unix; obsolete)***
 // - 'n' network special file (HP-UX)***
 // - 'p' fifo (named pipe)
 // - 'P' port***
@@ -74,7 +77,10 @@
 // - 'w' whiteout (4.4BSD)***
 // - '?' some other file type
 //
-// Note: ** = not implemented by GoLang
+// Note: *** = not implemented by GoLang, Mode carries these types but
+// Mode.FileMode maps them to fs.ModeIrregular.  GNU ls shows both
+// multiplexed types as 'm', the block variant uses 'M' here so that every
+// type survives String and Parse.
 
 package unixmode
 
@@ -109,6 +115,19 @@ const (
 	ModeSymlink    Mode = 0120000 /* symbolic link */
 	ModeSocket     Mode = 0140000 /* socket */
 
+	// Nonstandard types, these are not found on Linux.  Only the door,
+	// whiteout, multiplexed, and network values match the systems which
+	// define them, the others have no common value and are assigned one of
+	// the free codes here.  Solaris puts its event port on 0160000, which
+	// collides with the 4.4BSD whiteout.
+	ModeMultiplexedChar  Mode = 0030000 /* multiplexed character special (V7) */
+	ModePort             Mode = 0050000 /* event port (Solaris) */
+	ModeMultiplexedBlock Mode = 0070000 /* multiplexed block special (V7) */
+	ModeNetwork          Mode = 0110000 /* network special (HP-UX) */
+	ModeContiguous       Mode = 0130000 /* high performance, contiguous data file */
+	ModeDoor             Mode = 0150000 /* door (Solaris) */
+	ModeWhiteout         Mode = 0160000 /* whiteout (4.4BSD) */
+
 	// Set ID / Sticky (middle 3 bits)
 	ModeSetuid Mode = 0004000 /* set-user-ID on execution */
 	ModeSetgid Mode = 0002000 /* set-group-ID on execution */
@@ -153,22 +172,9 @@ func FileModeTypeLetter(m fs.FileMode) byte {
 	case fs.ModeSocket:
 		return 's'
 
-		//  /* Nonstandard file types.  */
-		//  if (S_ISCTG (bits))
-		//    return 'C';
-		//  if (S_ISDOOR (bits))
-		//    return 'D';
-		//  if (S_ISMPB (bits) || S_ISMPC (bits) || S_ISMPX (bits))
-		//    return 'm';
-		//  if (S_ISNWK (bits))
-		//    return 'n';
-		//  if (S_ISPORT (bits))
-		//    return 'P';
-		//  if (S_ISWHT (bits))
-		//    return 'w';
+		/* Nonstandard file types have no fs.FileMode bits.  */
 	}
 
-	fmt.Printf("mode %0o\n", m&fs.ModeType)
 	return '?'
 }
 
@@ -194,23 +200,24 @@ func (m Mode) TypeLetter() byte {
 	/* Other file types (though not letters) standardized by POSIX.  */
 	case ModeSocket:
 		return 's'
+
+	/* Nonstandard file types.  */
+	case ModeContiguous:
+		return 'C'
+	case ModeDoor:
+		return 'D'
+	case ModeMultiplexedChar:
+		return 'm'
+	case ModeMultiplexedBlock:
+		return 'M'
+	case ModeNetwork:
+		return 'n'
+	case ModePort:
+		return 'P'
+	case ModeWhiteout:
+		return 'w'
 	}
 
-	//  /* Nonstandard file types.  */
-	//  if (S_ISCTG (bits))
-	//    return 'C';
-	//  if (S_ISDOOR (bits))
-	//    return 'D';
-	//  if (S_ISMPB (bits) || S_ISMPC (bits) || S_ISMPX (bits))
-	//    return 'm';
-	//  if (S_ISNWK (bits))
-	//    return 'n';
-	//  if (S_ISPORT (bits))
-	//    return 'P';
-	//  if (S_ISWHT (bits))
-	//    return 'w';
-
-	fmt.Printf("mode %0o\n", m&ModeTypeMask)
 	return '?'
 }
 
@@ -310,11 +317,38 @@ func setIf(c *byte, test bool, t, f byte) {
 // "-rwsrwxrwx"  - 10 bytes, Lower 12 bits and includes setting the file ModeType
 //
 // "-rwsrwxrwx " - 11 bytes, Compatibility with newer os's with ACLs and SELinux contexts
+//
+// The 11th byte must be one of ' ', '+', or '.', use ParseExtras to learn
+// which one was given.
 func Parse(in string) (Mode, error) {
+	m, _, err := ParseExtras(in)
+	return m, err
+}
+
+// ParseExtras is Parse which also reports the Extras found in the 11th byte
+// of the string, as printed by ls -l.
+func ParseExtras(in string) (Mode, Extras, error) {
+	m, err := parse(in)
+	if err != nil || len(in) < 11 {
+		return m, 0, err
+	}
+	switch in[10] {
+	case ' ':
+		return m, 0, nil
+	case '+':
+		return m, ExtraACL, nil
+	case '.':
+		return m, ExtraSecurityContext, nil
+	}
+	return 0, 0, &ParseError{Input: in, Offset: 10, Byte: in[10], Expected: " +.", Err: ErrorMode}
+}
+
+func parse(orig string) (Mode, error) {
 	var m Mode
+	in, shift := orig, 0
 	switch len(in) {
 	case 9: // Assume a file and only parse the lower bits
-		in = "-" + in
+		in, shift = "-"+in, 1
 	case 10, 11: // For compatibility with 4.4BSD strmode
 		switch in[0] {
 		case '-':
@@ -331,14 +365,28 @@ func Parse(in string) (Mode, error) {
 			m = m | ModeNamedPipe
 		case 's':
 			m = m | ModeSocket
+		case 'C':
+			m = m | ModeContiguous
+		case 'D':
+			m = m | ModeDoor
+		case 'm':
+			m = m | ModeMultiplexedChar
+		case 'M':
+			m = m | ModeMultiplexedBlock
+		case 'n':
+			m = m | ModeNetwork
+		case 'P':
+			m = m | ModePort
+		case 'w':
+			m = m | ModeWhiteout
 		default:
-			return 0, ErrorMode
+			return 0, &ParseError{Input: orig, Offset: 0, Byte: in[0], Expected: typeLetters, Err: ErrorMode}
 		}
 	default:
-		return 0, ErrorModeLength
+		return 0, &ParseError{Input: orig, Offset: -1, Err: ErrorModeLength}
 	}
 
-	var err []string
+	var err *ParseError
 	setBitIf(&m, &err, in, 1, 'r', ModeReadUser)
 	setBitIf(&m, &err, in, 2, 'w', ModeWriteUser)
 	setBitIfIf(&m, &err, in, 3, 's', 'S', 'x', ModeSetuid, ModeExecUser)
@@ -348,70 +396,89 @@ func Parse(in string) (Mode, error) {
 	setBitIf(&m, &err, in, 7, 'r', ModeReadOther)
 	setBitIf(&m, &err, in, 8, 'w', ModeWriteOther)
 	setBitIfIf(&m, &err, in, 9, 't', 'T', 'x', ModeSticky, ModeExecOther)
-	if len(err) == 0 {
+	if err == nil {
 		return m, nil
 	}
-	return 0, errors.New(strings.Join(err, ","))
+	err.Input, err.Offset = orig, err.Offset-shift
+	return 0, err
 }
 
-// A function to parse a fs.FileMode string into the standard fs.FileMode
+// The type letters accepted by Parse.
+const typeLetters = "-dcblpsCDmMnPw"
+
+// ParseFileMode is the inverse of fs.FileMode.String, it parses strings like
+// "dugrwxr-xr-x" or "-rw-r--r--" back into the fs.FileMode.  The flag letters
+// must appear in the order String writes them, "dalTLDpSugct?", each at most
+// once, or be a single '-' when no flag is set.  For every fs.FileMode m
+// whose bits 9 to 18 are clear, ParseFileMode(m.String()) returns m.
+//
+// The ls style letters 's', 'S', 't', and 'T' are also accepted in the
+// execute positions and set fs.ModeSetuid, fs.ModeSetgid, or fs.ModeSticky.
 func ParseFileMode(in string) (fs.FileMode, error) {
+	if len(in) < 10 || len(in) > 9+len(fileModeLetters) {
+		return 0, &ParseError{Input: in, Offset: -1, Err: ErrorModeLength}
+	}
 	var m fs.FileMode
-	for _, c := range in[:len(in)-9] {
-		switch c {
-		case 'd':
-			m |= fs.ModeDir
-		case 'a':
-			m |= fs.ModeAppend
-		case 'l':
-			m |= fs.ModeExclusive
-		case 'T':
-			m |= fs.ModeTemporary
-		case 'L':
-			m |= fs.ModeSymlink
-		case 'D':
-			m |= fs.ModeDevice
-		case 'p':
-			m |= fs.ModeNamedPipe
-		case 'S':
-			m |= fs.ModeSocket
-		case 'u':
-			m |= fs.ModeSetuid
-		case 'g':
-			m |= fs.ModeSetgid
-		case 'c':
-			m |= fs.ModeCharDevice
-		case 't':
-			m |= fs.ModeSticky
-		case '?':
-			m |= fs.ModeIrregular
-		default:
-			return 0, ErrorMode
+	flags := in[:len(in)-9]
+	if flags != "-" {
+		next := 0
+		for i := 0; i < len(flags); i++ {
+			j := strings.IndexByte(fileModeLetters[next:], flags[i])
+			if j < 0 {
+				return 0, &ParseError{Input: in, Offset: i, Byte: flags[i], Expected: fileModeLetters[next:], Err: ErrorMode}
+			}
+			next += j
+			m |= 1 << (31 - next)
+			next++
 		}
 	}
+
 	const rwx = "rwxrwxrwx"
-	for i, c := range in[len(in)-9:] {
-		switch byte(c) {
-		case rwx[i]:
+	for i, c := range []byte(in[len(in)-9:]) {
+		var set, unset byte
+		var special fs.FileMode
+		switch i {
+		case 2:
+			set, unset, special = 's', 'S', fs.ModeSetuid
+		case 5:
+			set, unset, special = 's', 'S', fs.ModeSetgid
+		case 8:
+			set, unset, special = 't', 'T', fs.ModeSticky
+		}
+		switch {
+		case c == rwx[i]:
 			m |= 1 << (8 - i)
-		case '-':
+		case c == '-':
+		case special != 0 && c == set:
+			m |= 1<<(8-i) | special
+		case special != 0 && c == unset:
+			m |= special
 		default:
-			return 0, ErrorMode
+			expected := []byte{rwx[i], '-'}
+			if special != 0 {
+				expected = append(expected, set, unset)
+			}
+			return 0, &ParseError{Input: in, Offset: len(in) - 9 + i, Byte: c, Expected: string(expected), Err: ErrorMode}
 		}
 	}
 	return m, nil
 }
 
-func setBitIf(m *Mode, err *[]string, in string, strPos int, t byte, bitPos Mode) {
+// The flag letters of fs.FileMode.String, from bit 31 downwards.
+const fileModeLetters = "dalTLDpSugct?"
+
+func setBitIf(m *Mode, err **ParseError, in string, strPos int, t byte, bitPos Mode) {
 	switch in[strPos] {
 	case t:
 		*m = *m | bitPos
 	case '-':
 	default:
-		*err = append(*err, fmt.Sprintf("Invalid %q at position %d", in[strPos], strPos))
+		if *err == nil {
+			*err = &ParseError{Offset: strPos, Byte: in[strPos], Expected: string([]byte{t, '-'}), Err: ErrorMode}
+		}
 	}
 }
-func setBitIfIf(m *Mode, err *[]string, in string, strPos int, tt, tf, ft byte, bitPos1, bitPos2 Mode) {
+func setBitIfIf(m *Mode, err **ParseError, in string, strPos int, tt, tf, ft byte, bitPos1, bitPos2 Mode) {
 	switch in[strPos] {
 	case tt:
 		*m = *m | bitPos1 | bitPos2
@@ -421,7 +488,9 @@ func setBitIfIf(m *Mode, err *[]string, in string, strPos int, tt, tf, ft byte,
 		*m = *m | bitPos2
 	case '-':
 	default:
-		*err = append(*err, fmt.Sprintf("Invalid %q at position %d", in[strPos], strPos))
+		if *err == nil {
+			*err = &ParseError{Offset: strPos, Byte: in[strPos], Expected: string([]byte{tt, tf, ft, '-'}), Err: ErrorMode}
+		}
 	}
 }
 
@@ -430,6 +499,46 @@ var (
 	ErrorMode       = errors.New("Invalid Mode")
 )
 
+// A ParseError records which byte of a mode string could not be parsed.  It
+// wraps ErrorMode or ErrorModeLength, or ErrorInodeFlags or
+// ErrorInodeFlagsLength when returned by ParseInodeFlags, so errors.Is can be
+// used to tell them apart.
+type ParseError struct {
+	Input    string // the string given to the parser
+	Offset   int    // offset of the offending byte in Input, -1 for a length error
+	Byte     byte   // the offending byte
+	Expected string // the bytes which would have been accepted
+	Err      error  // ErrorMode, ErrorModeLength, ErrorInodeFlags, or ErrorInodeFlagsLength
+}
+
+func (e *ParseError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("%s %d for %q", e.Err, len(e.Input), e.Input)
+	}
+	return fmt.Sprintf("Invalid %q at position %d", e.Byte, e.Offset)
+}
+
+// Unwrap returns the underlying error, such as ErrorMode or ErrorModeLength.
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// CheckType returns an error wrapping ErrorModeType when the type bits of m
+// are not one of the known types.  A Mode without type bits passes.
+func (m Mode) CheckType() error {
+	if m.validType() {
+		return nil
+	}
+	return fmt.Errorf("%w: %06o", ErrorModeType, uint16(m&ModeTypeMask))
+}
+
+// FileModeCheckType returns an error wrapping ErrorModeType when the type
+// bits of m are not a combination FileModeTypeLetter knows.
+func FileModeCheckType(m fs.FileMode) error {
+	if FileModeTypeLetter(m) != '?' {
+		return nil
+	}
+	return fmt.Errorf("%w: %v", ErrorModeType, m&fs.ModeType)
+}
+
 // IsDir reports whether m describes a directory.
 // That is, it tests for the ModeDir bit being set in m.
 func (m Mode) IsDir() bool {
@@ -512,6 +621,10 @@ func New(m fs.FileMode) Mode {
 }
 
 // Create a FileMode(uint32) from a unixmode.Mode(uint16) by cross referencing bits.
+//
+// The nonstandard types, such as ModeDoor or ModeWhiteout, have no
+// fs.FileMode equivalent and are returned as fs.ModeIrregular.  Converting
+// those back with New does not restore the type.
 func (m Mode) FileMode() fs.FileMode {
 	out := fs.FileMode(m)&0777 | fs.FileMode(m)&06000<<12 | fs.FileMode(m)&01000<<11
 	switch m & ModeTypeMask {
@@ -534,6 +647,11 @@ func (m Mode) FileMode() fs.FileMode {
 	/* Other file types (though not letters) standardized by POSIX.  */
 	case ModeSocket:
 		out |= fs.ModeSocket
+
+	/* Nonstandard file types.  */
+	case ModeContiguous, ModeDoor, ModeMultiplexedChar, ModeMultiplexedBlock,
+		ModeNetwork, ModePort, ModeWhiteout:
+		out |= fs.ModeIrregular
 	}
 	return out
 }
@@ -1,10 +1,12 @@
 package unixmode_test
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
+	"testing"
 
 	"github.com/pschou/go-unixmode"
 )
@@ -274,3 +276,88 @@ func ExampleParseFileMode() {
 	// Output:
 	// File Mode: 10060000744 augrwxr--r-- == augrwxr--r--
 }
+
+func ExampleMode_TypeLetter_nonstandard() {
+	for _, in := range []string{"Crw-r--r--", "Drw-r--r--", "mrw-r--r--", "Mrw-r--r--", "nrw-r--r--", "Prw-r--r--", "wrw-r--r--"} {
+		m, _ := unixmode.Parse(in)
+		fmt.Printf("%c %s %v\n", m.TypeLetter(), m.Octal(unixmode.OctalFull), m.FileMode())
+	}
+	// Output:
+	// C 130644 ?rw-r--r--
+	// D 150644 ?rw-r--r--
+	// m 030644 ?rw-r--r--
+	// M 070644 ?rw-r--r--
+	// n 110644 ?rw-r--r--
+	// P 050644 ?rw-r--r--
+	// w 160644 ?rw-r--r--
+}
+
+func ExampleParseError() {
+	for _, in := range []string{"rwxr-zr-x", "qrwxr-xr-x", "rwx"} {
+		_, err := unixmode.Parse(in)
+		var pe *unixmode.ParseError
+		if errors.As(err, &pe) {
+			fmt.Printf("%v: offset=%d byte=%q expected=%q mode=%v length=%v\n", err, pe.Offset, pe.Byte, pe.Expected,
+				errors.Is(err, unixmode.ErrorMode), errors.Is(err, unixmode.ErrorModeLength))
+		}
+	}
+	// Output:
+	// Invalid 'z' at position 5: offset=5 byte='z' expected="sSx-" mode=true length=false
+	// Invalid 'q' at position 0: offset=0 byte='q' expected="-dcblpsCDmMnPw" mode=true length=false
+	// Invalid Mode Length 3 for "rwx": offset=-1 byte='\x00' expected="" mode=false length=true
+}
+
+func ExampleMode_CheckType() {
+	fmt.Println(unixmode.Mode(0170644).CheckType())
+	fmt.Println(unixmode.Mode(0100644).CheckType())
+	fmt.Printf("%c\n", unixmode.Mode(0170644).TypeLetter())
+	// Output:
+	// Invalid Mode Type: 170000
+	// <nil>
+	// ?
+}
+
+func ExampleParseFileMode_invalid() {
+	for _, in := range []string{"", "rwxr-xr-x", "gurwxr-xr-x", "ddrwxr-xr-x", "-rwxr-zr-x", "Lrwsr-Sr-T"} {
+		fm, err := unixmode.ParseFileMode(in)
+		fmt.Println(fm, err)
+	}
+	// Output:
+	// ---------- Invalid Mode Length 0 for ""
+	// ---------- Invalid Mode Length 9 for "rwxr-xr-x"
+	// ---------- Invalid 'u' at position 1
+	// ---------- Invalid 'd' at position 1
+	// ---------- Invalid 'z' at position 6
+	// Lugtrwxr--r-- <nil>
+}
+
+func TestParseFileModeRoundTrip(t *testing.T) {
+	// Every combination of the flag bits and the permission bits, bits 9 to
+	// 18 are never printed by fs.FileMode.String.
+	const flags = 1<<13 - 1
+	for f := fs.FileMode(0); f <= flags; f++ {
+		for p := fs.FileMode(0); p <= 0777; p++ {
+			m := f<<19 | p
+			got, err := unixmode.ParseFileMode(m.String())
+			if err != nil || got != m {
+				t.Fatalf("ParseFileMode(%q) = %v, %v; want %v", m.String(), got, err, m)
+			}
+		}
+	}
+}
+
+func TestTypeLetterRoundTrip(t *testing.T) {
+	for i := 1; i < 16; i++ {
+		typ := unixmode.Mode(i << 12)
+		if typ.TypeLetter() == '?' {
+			continue
+		}
+		for perm := unixmode.Mode(0); perm <= 07777; perm++ {
+			m := typ | perm
+			got, err := unixmode.Parse(m.String())
+			if err != nil || got != m {
+				t.Fatalf("Parse(%q) = %06o, %v, want %06o", m.String(), got, err, m)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// An XattrNamespace is the prefix of an extended attribute name, which
+// decides who may read and write the attribute.
+type XattrNamespace string
+
+const (
+	XattrUser     XattrNamespace = "user."     /* any user with file access */
+	XattrTrusted  XattrNamespace = "trusted."  /* CAP_SYS_ADMIN only */
+	XattrSecurity XattrNamespace = "security." /* security modules, such as SELinux labels and capabilities */
+	XattrSystem   XattrNamespace = "system."   /* kernel objects, such as ACLs */
+)
+
+var (
+	ErrorXattrName        = errors.New("Invalid Extended Attribute Name")
+	ErrorXattrNotFound    = errors.New("Extended Attribute Not Found")
+	ErrorXattrUnsupported = errors.New("Extended Attributes Not Supported")
+)
+
+// XattrName joins a namespace and a name, like XattrName(XattrUser, "foo")
+// for "user.foo".
+func XattrName(ns XattrNamespace, name string) string {
+	return string(ns) + name
+}
+
+// SplitXattrName splits a full attribute name into its namespace and the
+// name within it.  Names outside the four Linux namespaces are rejected.
+func SplitXattrName(full string) (XattrNamespace, string, error) {
+	for _, ns := range []XattrNamespace{XattrUser, XattrTrusted, XattrSecurity, XattrSystem} {
+		if strings.HasPrefix(full, string(ns)) && len(full) > len(ns) {
+			return ns, full[len(ns):], nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: %q", ErrorXattrName, full)
+}
+
+// An XattrError is returned by the extended attribute functions.  It matches
+// ErrorXattrNotFound with errors.Is when the attribute does not exist, and
+// ErrorXattrUnsupported when the file system or namespace does not support
+// extended attributes, as well as the underlying Err.
+type XattrError struct {
+	Op   string
+	Path string
+	Name string
+	Err  error
+}
+
+func (e *XattrError) Error() string {
+	if e.Name == "" {
+		return e.Op + " " + e.Path + ": " + e.Err.Error()
+	}
+	return e.Op + " " + e.Path + " " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *XattrError) Unwrap() error { return e.Err }
+
+// Is matches the errno values behind ErrorXattrNotFound and
+// ErrorXattrUnsupported.
+func (e *XattrError) Is(target error) bool {
+	switch target {
+	case ErrorXattrNotFound:
+		return xattrNotFound != nil && errors.Is(e.Err, xattrNotFound)
+	case ErrorXattrUnsupported:
+		return xattrUnsupported != nil && errors.Is(e.Err, xattrUnsupported)
+	}
+	return false
+}
@@ -0,0 +1,305 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// Flags for Setxattr.
+const (
+	XattrCreate  = 0x1 /* fail if the attribute exists */
+	XattrReplace = 0x2 /* fail if the attribute does not exist */
+)
+
+// The errno values behind ErrorXattrNotFound and ErrorXattrUnsupported.
+var xattrNotFound, xattrUnsupported error = syscall.ENODATA, syscall.ENOTSUP
+
+// Getxattr reads an extended attribute, following symbolic links.
+func Getxattr(path, name string) ([]byte, error) {
+	return xattrGet("getxattr", path, name, true)
+}
+
+// Lgetxattr reads an extended attribute of a symbolic link itself.
+func Lgetxattr(path, name string) ([]byte, error) {
+	return xattrGet("lgetxattr", path, name, false)
+}
+
+// Fgetxattr reads an extended attribute of an opened file.
+func Fgetxattr(f *os.File, name string) (val []byte, err error) {
+	ferr := fileControl(f, func(fd int) error {
+		val, err = fdXattr(fd).get(name)
+		return nil
+	})
+	return val, xattrErr("fgetxattr", f.Name(), name, firstErr(ferr, err))
+}
+
+// Listxattr lists the extended attribute names of a file, following
+// symbolic links.  Only the names the caller may read are listed.
+func Listxattr(path string) ([]string, error) {
+	return xattrList("listxattr", path, true)
+}
+
+// Llistxattr lists the extended attribute names of a symbolic link itself.
+func Llistxattr(path string) ([]string, error) {
+	return xattrList("llistxattr", path, false)
+}
+
+// Flistxattr lists the extended attribute names of an opened file.
+func Flistxattr(f *os.File) (names []string, err error) {
+	ferr := fileControl(f, func(fd int) error {
+		names, err = fdXattr(fd).list()
+		return nil
+	})
+	return names, xattrErr("flistxattr", f.Name(), "", firstErr(ferr, err))
+}
+
+// Setxattr writes an extended attribute, following symbolic links.  The
+// flags may be XattrCreate or XattrReplace.
+func Setxattr(path, name string, val []byte, flags int) error {
+	t, err := pathXattr(path, true)
+	if err == nil {
+		err = t.set(name, val, flags)
+	}
+	return xattrErr("setxattr", path, name, err)
+}
+
+// Lsetxattr writes an extended attribute of a symbolic link itself.
+func Lsetxattr(path, name string, val []byte, flags int) error {
+	t, err := pathXattr(path, false)
+	if err == nil {
+		err = t.set(name, val, flags)
+	}
+	return xattrErr("lsetxattr", path, name, err)
+}
+
+// Fsetxattr writes an extended attribute of an opened file.
+func Fsetxattr(f *os.File, name string, val []byte, flags int) error {
+	var err error
+	ferr := fileControl(f, func(fd int) error {
+		err = fdXattr(fd).set(name, val, flags)
+		return nil
+	})
+	return xattrErr("fsetxattr", f.Name(), name, firstErr(ferr, err))
+}
+
+// Removexattr removes an extended attribute, following symbolic links.
+func Removexattr(path, name string) error {
+	t, err := pathXattr(path, true)
+	if err == nil {
+		err = t.remove(name)
+	}
+	return xattrErr("removexattr", path, name, err)
+}
+
+// Lremovexattr removes an extended attribute of a symbolic link itself.
+func Lremovexattr(path, name string) error {
+	t, err := pathXattr(path, false)
+	if err == nil {
+		err = t.remove(name)
+	}
+	return xattrErr("lremovexattr", path, name, err)
+}
+
+// Fremovexattr removes an extended attribute of an opened file.
+func Fremovexattr(f *os.File, name string) error {
+	var err error
+	ferr := fileControl(f, func(fd int) error {
+		err = fdXattr(fd).remove(name)
+		return nil
+	})
+	return xattrErr("fremovexattr", f.Name(), name, firstErr(ferr, err))
+}
+
+func xattrGet(op, path, name string, follow bool) ([]byte, error) {
+	t, err := pathXattr(path, follow)
+	if err != nil {
+		return nil, xattrErr(op, path, name, err)
+	}
+	val, err := t.get(name)
+	return val, xattrErr(op, path, name, err)
+}
+
+func xattrList(op, path string, follow bool) ([]string, error) {
+	t, err := pathXattr(path, follow)
+	if err != nil {
+		return nil, xattrErr(op, path, "", err)
+	}
+	names, err := t.list()
+	return names, xattrErr(op, path, "", err)
+}
+
+func xattrErr(op, path, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &XattrError{Op: op, Path: path, Name: name, Err: err}
+}
+
+func firstErr(a, b error) error {
+	if a != nil {
+		return a
+	}
+	return b
+}
+
+// getxattr and lgetxattr return the bare errno, for callers inside the
+// package which switch on it.
+func getxattr(path, name string) ([]byte, error) {
+	t, err := pathXattr(path, true)
+	if err != nil {
+		return nil, err
+	}
+	return t.get(name)
+}
+
+func lgetxattr(path, name string) ([]byte, error) {
+	t, err := pathXattr(path, false)
+	if err != nil {
+		return nil, err
+	}
+	return t.get(name)
+}
+
+// An xattrTarget is a file named by a path, followed or not, or by a
+// descriptor, together with the matching syscalls.
+type xattrTarget struct {
+	path              *byte
+	fd                int
+	sysGet, sysList   uintptr
+	sysSet, sysRemove uintptr
+}
+
+func pathXattr(path string, follow bool) (*xattrTarget, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	if follow {
+		return &xattrTarget{path: p, sysGet: syscall.SYS_GETXATTR, sysList: syscall.SYS_LISTXATTR,
+			sysSet: syscall.SYS_SETXATTR, sysRemove: syscall.SYS_REMOVEXATTR}, nil
+	}
+	return &xattrTarget{path: p, sysGet: syscall.SYS_LGETXATTR, sysList: syscall.SYS_LLISTXATTR,
+		sysSet: syscall.SYS_LSETXATTR, sysRemove: syscall.SYS_LREMOVEXATTR}, nil
+}
+
+func fdXattr(fd int) *xattrTarget {
+	return &xattrTarget{fd: fd, sysGet: syscall.SYS_FGETXATTR, sysList: syscall.SYS_FLISTXATTR,
+		sysSet: syscall.SYS_FSETXATTR, sysRemove: syscall.SYS_FREMOVEXATTR}
+}
+
+// call runs trap with the path or descriptor of t as the first argument.
+// The pointers are converted in the Syscall6 argument list, so they are kept
+// alive and in place for the duration of the call.
+func (t *xattrTarget) call(trap uintptr, a2, a3 unsafe.Pointer, a4, a5 uintptr) (int, error) {
+	var r uintptr
+	var e syscall.Errno
+	if t.path != nil {
+		r, _, e = syscall.Syscall6(trap, uintptr(unsafe.Pointer(t.path)), uintptr(a2), uintptr(a3), a4, a5, 0)
+	} else {
+		r, _, e = syscall.Syscall6(trap, uintptr(t.fd), uintptr(a2), uintptr(a3), a4, a5, 0)
+	}
+	if e != 0 {
+		return 0, e
+	}
+	return int(r), nil
+}
+
+// sized probes the size of a value, then reads it, retrying when the value
+// grows in between.
+func sized(read func(dest []byte) (int, error)) ([]byte, error) {
+	for {
+		sz, err := read(nil)
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, sz)
+		sz, err = read(buf)
+		if err == syscall.ERANGE {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return buf[:sz], nil
+	}
+}
+
+// bufPtr returns the address of the first byte of b, or nil when b is
+// empty, which asks the kernel for the size only.
+func bufPtr(b []byte) unsafe.Pointer {
+	if len(b) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(&b[0])
+}
+
+func (t *xattrTarget) get(name string) ([]byte, error) {
+	n, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	return sized(func(dest []byte) (int, error) {
+		return t.call(t.sysGet, unsafe.Pointer(n), bufPtr(dest), uintptr(len(dest)), 0)
+	})
+}
+
+func (t *xattrTarget) list() ([]string, error) {
+	b, err := sized(func(dest []byte) (int, error) {
+		// The list and its size take the second and third arguments
+		var r uintptr
+		var e syscall.Errno
+		if t.path != nil {
+			r, _, e = syscall.Syscall6(t.sysList, uintptr(unsafe.Pointer(t.path)), uintptr(bufPtr(dest)), uintptr(len(dest)), 0, 0, 0)
+		} else {
+			r, _, e = syscall.Syscall6(t.sysList, uintptr(t.fd), uintptr(bufPtr(dest)), uintptr(len(dest)), 0, 0, 0)
+		}
+		if e != 0 {
+			return 0, e
+		}
+		return int(r), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, n := range strings.Split(string(b), "\x00") {
+		if n != "" {
+			names = append(names, n)
+		}
+	}
+	return names, nil
+}
+
+func (t *xattrTarget) set(name string, val []byte, flags int) error {
+	n, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return err
+	}
+	_, err = t.call(t.sysSet, unsafe.Pointer(n), bufPtr(val), uintptr(len(val)), uintptr(flags))
+	return err
+}
+
+func (t *xattrTarget) remove(name string) error {
+	n, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return err
+	}
+	_, err = t.call(t.sysRemove, unsafe.Pointer(n), nil, 0, 0)
+	return err
+}
@@ -0,0 +1,33 @@
+package unixmode_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleSetxattr() {
+	f, _ := os.CreateTemp("", "xattr")
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	err := unixmode.Setxattr(f.Name(), "user.origin", []byte("example"), unixmode.XattrCreate)
+	fmt.Println(err)
+	v, err := unixmode.Fgetxattr(f, "user.origin")
+	fmt.Printf("%s %v\n", v, err)
+	names, _ := unixmode.Listxattr(f.Name())
+	fmt.Println(names)
+
+	err = unixmode.Removexattr(f.Name(), "user.origin")
+	fmt.Println(err)
+	_, err = unixmode.Getxattr(f.Name(), "user.origin")
+	fmt.Println(errors.Is(err, unixmode.ErrorXattrNotFound), errors.Is(err, unixmode.ErrorXattrUnsupported))
+	// Output:
+	// <nil>
+	// example <nil>
+	// [user.origin]
+	// <nil>
+	// true false
+}
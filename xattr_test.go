@@ -0,0 +1,17 @@
+package unixmode_test
+
+import (
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleSplitXattrName() {
+	fmt.Println(unixmode.SplitXattrName("security.selinux"))
+	fmt.Println(unixmode.SplitXattrName(unixmode.XattrName(unixmode.XattrUser, "mime_type")))
+	fmt.Println(unixmode.SplitXattrName("bogus.name"))
+	// Output:
+	// security. selinux <nil>
+	// user. mime_type <nil>
+	//   Invalid Extended Attribute Name: "bogus.name"
+}
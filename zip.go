@@ -0,0 +1,76 @@
+// Copyright 2023 github.com/pschou/go-unixmode
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unixmode
+
+import (
+	"archive/zip"
+	"strings"
+)
+
+// The Unix host systems recorded in the upper byte of zip.FileHeader.CreatorVersion
+// and the MS-DOS attribute bits kept in the low byte of ExternalAttrs.
+const (
+	zipCreatorUnix   = 3
+	zipCreatorMacOSX = 19
+
+	zipMSDOSReadOnly = 0x01
+	zipMSDOSDir      = 0x10
+)
+
+// FromZipHeader returns the Mode of a zip entry.  When the entry was made on
+// Unix or macOS the st_mode is read from the top 16 bits of ExternalAttrs
+// and the result is true.  Otherwise the Mode is derived from the MS-DOS
+// directory and read-only attributes, as 0777 for directories and 0666 for
+// files less any write bits, and the result is false.  A name ending in '/'
+// is always a directory.
+func FromZipHeader(h *zip.FileHeader) (Mode, bool) {
+	isDir := strings.HasSuffix(h.Name, "/")
+	switch h.CreatorVersion >> 8 {
+	case zipCreatorUnix, zipCreatorMacOSX:
+		if m := Mode(h.ExternalAttrs >> 16); m != 0 {
+			switch {
+			case isDir:
+				m = ModeDir | m.Perm()
+			case m.Type() == 0:
+				m |= ModeRegular
+			}
+			return m, true
+		}
+	}
+	m := Mode(ModeRegular | 0666)
+	if isDir || h.ExternalAttrs&zipMSDOSDir != 0 {
+		m = ModeDir | 0777
+	}
+	if h.ExternalAttrs&zipMSDOSReadOnly != 0 {
+		m &^= 0222
+	}
+	return m, false
+}
+
+// ApplyToZipHeader stores the Mode into h, marking the entry as made on Unix.
+// All 16 bits of the Mode are kept, so every type, including symbolic links
+// whose target is the entry content, reads back unchanged with FromZipHeader.
+// The MS-DOS directory and read-only attributes are set as well for readers
+// which do not understand Unix modes.
+func (m Mode) ApplyToZipHeader(h *zip.FileHeader) {
+	h.CreatorVersion = h.CreatorVersion&0xff | zipCreatorUnix<<8
+	h.ExternalAttrs = uint32(m) << 16
+	if m.Type() == ModeDir {
+		h.ExternalAttrs |= zipMSDOSDir
+	}
+	if m&ModeWriteUser == 0 {
+		h.ExternalAttrs |= zipMSDOSReadOnly
+	}
+}
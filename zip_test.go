@@ -0,0 +1,56 @@
+package unixmode_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+
+	"github.com/pschou/go-unixmode"
+)
+
+func ExampleFromZipHeader() {
+	for _, h := range []*zip.FileHeader{
+		{Name: "run.sh", CreatorVersion: 3 << 8, ExternalAttrs: 0100755 << 16},
+		{Name: "link", CreatorVersion: 3 << 8, ExternalAttrs: 0120777 << 16},
+		{Name: "README", CreatorVersion: 0, ExternalAttrs: 0x01},
+		{Name: "docs/", CreatorVersion: 11 << 8, ExternalAttrs: 0x10},
+	} {
+		m, unix := unixmode.FromZipHeader(h)
+		fmt.Printf("%s %c%s %v\n", h.Name, m.TypeLetter(), m.PermString(), unix)
+	}
+	// Output:
+	// run.sh -rwxr-xr-x true
+	// link lrwxrwxrwx true
+	// README -r--r--r-- false
+	// docs/ drwxrwxrwx false
+}
+
+func ExampleMode_ApplyToZipHeader() {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range []struct {
+		name string
+		mode unixmode.Mode
+		body string
+	}{
+		{"bin/", unixmode.ModeDir | 0755, ""},
+		{"bin/tool", unixmode.ModeRegular | 04511, "#!/bin/sh\n"},
+		{"tool", unixmode.ModeSymlink | 0777, "bin/tool"},
+	} {
+		h := &zip.FileHeader{Name: e.name}
+		e.mode.ApplyToZipHeader(h)
+		w, _ := zw.CreateHeader(h)
+		w.Write([]byte(e.body))
+	}
+	zw.Close()
+
+	zr, _ := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	for _, f := range zr.File {
+		m, _ := unixmode.FromZipHeader(&f.FileHeader)
+		fmt.Printf("%s %s %#x\n", f.Name, m.Octal(unixmode.OctalFull), f.ExternalAttrs&0xff)
+	}
+	// Output:
+	// bin/ 040755 0x10
+	// bin/tool 104511 0x1
+	// tool 120777 0x0
+}